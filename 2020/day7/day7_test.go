@@ -1,11 +1,8 @@
 package day7
 
 import (
-	"fmt"
 	"reflect"
 	"testing"
-
-	"github.com/davecgh/go-spew/spew"
 )
 
 func TestPart1(t *testing.T) {
@@ -17,7 +14,9 @@ func TestPart1(t *testing.T) {
 		args args
 		want int
 	}{
-		// TODO: Add test cases.
+		{"sample", args{"testdata/sample.txt"}, 4},
+		{"transitive-muted-crimson", args{"testdata/transitive.txt"}, 10},
+		{"full", args{"testdata/full.txt"}, 254},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -28,90 +27,40 @@ func TestPart1(t *testing.T) {
 	}
 }
 
-func Test_splitRules(t *testing.T) {
-	type args struct {
-		filename string
+func Test_containedIn(t *testing.T) {
+	want := map[string][]string{
+		"bright white": {"light red", "dark orange"},
+		"muted yellow": {"light red", "dark orange"},
+		"shiny gold":   {"bright white", "muted yellow"},
+		"faded blue":   {"muted yellow", "dark olive", "vibrant plum"},
+		"dark olive":   {"shiny gold"},
+		"vibrant plum": {"shiny gold"},
+		"dotted black": {"dark olive", "vibrant plum"},
 	}
-	tests := []struct {
-		name string
-		args args
-		want []string
-	}{
-		{"sample", args{"testdata/sample.txt"}, []string{
-			"light red;1:bright white,2:muted yellow",
-			"dark orange;3:bright white,4:muted yellow",
-			"bright white;1:shiny gold",
-			"muted yellow;2:shiny gold,9:faded blue",
-			"shiny gold;1:dark olive,2:vibrant plum",
-			"dark olive;3:faded blue,4:dotted black",
-			"vibrant plum;5:faded blue,6:dotted black",
-			"faded blue;0:none",
-			"dotted black;0:none",
-		}},
-		{"transitive-muted-crimson", args{"testdata/transitive.txt"}, []string{
-			"wavy purple;2:shiny gold,4:mirrored maroon",
-			"pale magenta;2:muted orange,4:muted crimson,4:striped turquoise",
-			"muted fuchsia;4:plaid indigo,2:shiny gold",
-			"bob blue;2:vibrant fuchsia",
-			"shiny red;2:clear tomato,4:muted crimson,2:plaid gray,1:bright gold",
-			"mirrored tan;4:pale orange,3:plaid olive,5:muted crimson,5:posh salmon",
-			"shiny gold;4:posh coral,2:clear violet",
-			"clear green;3:muted crimson",
-			"mirrored plum;1:bright chartreuse,4:mirrored purple,1:dim turquoise,4:shiny gold",
-			"muted crimson;3:mirrored coral,4:light silver,2:shiny gold",
-			"vibrant fuchsia;3:dim cyan,2:muted crimson",
-		}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := splitRules(tt.args.filename); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("splitRules() = %v, want %v", got, tt.want)
-				fmt.Println("GOT:")
-				spew.Dump(got)
-				fmt.Println("WANT:")
-				spew.Dump(tt.want)
-			}
-		})
+	if got := containedIn("testdata/sample.txt"); !reflect.DeepEqual(got, want) {
+		t.Errorf("containedIn() = %v, want %v", got, want)
 	}
 }
 
 func Test_countBagContainsShinyGold(t *testing.T) {
-	type args struct {
-		allRules []string
-	}
 	tests := []struct {
-		name string
-		args args
-		want int
+		name    string
+		holders map[string][]string
+		want    int
 	}{
-		{"sample", args{[]string{
-			"light red;1:bright white,2:muted yellow",
-			"dark orange;3:bright white,4:muted yellow",
-			"bright white;1:shiny gold",
-			"muted yellow;2:shiny gold,9:faded blue",
-			"shiny gold;1:dark olive,2:vibrant plum",
-			"dark olive;3:faded blue,4:dotted black",
-			"vibrant plum;5:faded blue,6:dotted black",
-			"faded blue;0:none",
-			"dotted black;0:none",
-		}}, 4},
-		{"transitive-muted-crimson", args{[]string{
-			"wavy purple;2:shiny gold,4:mirrored maroon",
-			"pale magenta;2:muted orange,4:muted crimson,4:striped turquoise",
-			"muted fuchsia;4:plaid indigo,2:shiny gold",
-			"bob blue;2:vibrant fuchsia",
-			"shiny red;2:clear tomato,4:muted crimson,2:plaid gray,1:bright gold",
-			"mirrored tan;4:pale orange,3:plaid olive,5:muted crimson,5:posh salmon",
-			"shiny gold;4:posh coral,2:clear violet",
-			"clear green;3:muted crimson",
-			"mirrored plum;1:bright chartreuse,4:mirrored purple,1:dim turquoise,4:shiny gold",
-			"muted crimson;3:mirrored coral,4:light silver,2:shiny gold",
-			"vibrant fuchsia;3:dim cyan,2:muted crimson",
-		}}, 10},
+		{"nobody holds it", map[string][]string{"faded blue": {"shiny gold"}}, 0},
+		{"chain", map[string][]string{
+			"shiny gold":   {"bright white"},
+			"bright white": {"light red", "dark orange"},
+		}, 3},
+		{"cycle", map[string][]string{
+			"shiny gold": {"dim red"},
+			"dim red":    {"shiny gold"},
+		}, 1},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := countBagContainsShinyGold(tt.args.allRules); got != tt.want {
+			if got := countBagContainsShinyGold(tt.holders); got != tt.want {
 				t.Errorf("countBagContainsShinyGold() = %v, want %v", got, tt.want)
 			}
 		})
@@ -1,5 +1,10 @@
 module github.com/leowmjw/aoc2020
 
-go 1.15
+go 1.23.12
 
-require github.com/davecgh/go-spew v1.1.1
+require (
+	aoc v0.0.0
+	github.com/davecgh/go-spew v1.1.1
+)
+
+replace aoc => ../
@@ -1,5 +1,92 @@
 package day11
 
-func hasAdjacentFlashed() bool {
-	return false
+import (
+	"fmt"
+
+	"aoc/pkg/grid"
+	"aoc/pkg/viz"
+)
+
+// loadGrid reads the octopus energy levels; one digit per octopus
+func loadGrid(filename string) *grid.Grid[int] {
+	g, err := grid.Read(filename, grid.Digit)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// step raises every energy level by 1, flashes every octopus above 9 (which
+// in turn raises its neighbours) and resets the flashed ones to 0.
+// Returns the number of flashes.
+func step(g *grid.Grid[int]) int {
+	var queue []grid.Point
+	for p, v := range g.All() {
+		g.Set(p, v+1)
+		if v+1 > 9 {
+			queue = append(queue, p)
+		}
+	}
+	flashed := make(map[grid.Point]bool)
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if flashed[cur] {
+			continue
+		}
+		flashed[cur] = true
+		for n := range g.Neighbors8(cur) {
+			g.Set(n, g.Get(n)+1)
+			if g.Get(n) > 9 && !flashed[n] {
+				queue = append(queue, n)
+			}
+		}
+	}
+	for f := range flashed {
+		g.Set(f, 0)
+	}
+	return len(flashed)
+}
+
+// Part1 counts the flashes over the given number of steps
+func Part1(filename string, steps int) int {
+	g := loadGrid(filename)
+	total := 0
+	for i := 0; i < steps; i++ {
+		total += step(g)
+	}
+	return total
+}
+
+// Part2 finds the first step where every octopus flashes at once
+func Part2(filename string) int {
+	g := loadGrid(filename)
+	size := g.Width * g.Height
+	for i := 1; ; i++ {
+		if step(g) == size {
+			return i
+		}
+	}
+}
+
+// Visualize runs the given number of steps through anim; octopuses that
+// flashed in the step are highlighted
+func Visualize(filename string, steps int, anim *viz.Animator) error {
+	g := loadGrid(filename)
+	flashedNow := func(r, c int) bool { return g.Get(grid.Point{X: c, Y: r}) == 0 }
+	if err := anim.Start("start", viz.Digits(g.Rows(), nil, anim.Emphasis())); err != nil {
+		return err
+	}
+	total := 0
+	var title, frame string
+	for i := 0; i < steps; i++ {
+		n := step(g)
+		total += n
+		title = fmt.Sprintf("%d flashes, %d total", n, total)
+		frame = viz.Digits(g.Rows(), flashedNow, anim.Emphasis())
+		if err := anim.Step(title, frame); err != nil {
+			return err
+		}
+	}
+	return anim.Done(title, frame)
 }
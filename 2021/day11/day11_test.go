@@ -1,29 +1,61 @@
 package day11
 
 import (
+	"bytes"
 	"os"
+	"reflect"
 	"testing"
+
+	"aoc/pkg/viz"
 )
 
 func TestMain(m *testing.M) {
-	// Load sample data for use?
-	// Load result to do comparison with??
 	os.Exit(m.Run())
 }
 
-func Test_hasAdjacentFlashed(t *testing.T) {
+func Test_step(t *testing.T) {
 	tests := []struct {
-		name string
-		want bool
+		name        string
+		steps       int
+		wantFlashes int
+		want        [][]int
 	}{
-		// TODO: Add test cases.
-		{"happy #1", true},
+		{"one step", 1, 9, [][]int{
+			{3, 4, 5, 4, 3},
+			{4, 0, 0, 0, 4},
+			{5, 0, 0, 0, 5},
+			{4, 0, 0, 0, 4},
+			{3, 4, 5, 4, 3},
+		}},
+		{"two steps", 2, 9, loadGrid("testdata/sample-result.txt").Rows()},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := hasAdjacentFlashed(); got != tt.want {
-				t.Errorf("hasAdjacentFlashed() = %v, want %v", got, tt.want)
+			g := loadGrid("testdata/sample.txt")
+			flashes := 0
+			for i := 0; i < tt.steps; i++ {
+				flashes += step(g)
+			}
+			if flashes != tt.wantFlashes {
+				t.Errorf("step() flashes = %v, want %v", flashes, tt.wantFlashes)
+			}
+			if got := g.Rows(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("step() grid = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestVisualize(t *testing.T) {
+	want, err := os.ReadFile("testdata/sample-frames.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := Visualize("testdata/sample.txt", 2, viz.NewHeadless(&got, viz.Options{})); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != string(want) {
+		t.Errorf("Visualize() frames =\n%s\nwant\n%s", got.String(), want)
+	}
+}
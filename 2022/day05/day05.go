@@ -1,212 +1,138 @@
-package main
+package day05
 
 import (
 	"fmt"
 	"github.com/bitfield/script"
-	"github.com/emirpasic/gods/stacks/arraystack"
 	"strings"
+
+	"aoc/pkg/parse"
+	"aoc/pkg/viz"
 )
 
-func main() {
+// move is one "move N from A to B" instruction; stacks are 0-indexed
+type move struct {
+	numBlocks, fid, tid int
+}
 
-	fmt.Println("Day 05 ..")
-	//Part1("testdata/sample.txt")
-	//Part1("testdata/input.txt")
-	//Part2("testdata/sample.txt")
-	Part2("testdata/input.txt")
+// Part1 prints the top crates after the CrateMover 9000 rearrangement
+func Part1(filePath string) error {
+	return rearrange(filePath, false)
 }
 
-func Part1(filePath string) {
+// Part2 prints the top crates after the CrateMover 9001 rearrangement
+func Part2(filePath string) error {
+	return rearrange(filePath, true)
+}
+
+// rearrange applies every move in filePath and prints the crate on top of
+// each stack, skipping stacks left empty
+func rearrange(filePath string, crateMover9001 bool) error {
 	data, err := script.File(filePath).Slice()
 	if err != nil {
-		panic(err)
+		return err
+	}
+	queueSetup, moves, err := parseDrawing(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+	for _, m := range moves {
+		moveCrates(queueSetup, m, crateMover9001)
 	}
-	numOfStack := 0
-	executeAction := false
-	var queueSetup [][]string
-	var stacks []*arraystack.Stack
-	for _, l := range data {
-		if executeAction {
-			var numBlocks, fromStack, toStack int
-			//var SnumBlocks, SfromStack, StoStack string
-			//fmt.Println("NOW START ACTZION ..")
-			// DEBUG
-			//spew.Dump(stacks)
-			// Now in the instructions mode
-			_, err := fmt.Sscanf(l, "move %d from %d to %d", &numBlocks, &fromStack, &toStack)
-			if err != nil {
-				//panic(err)
-				continue
-			}
-			fid := fromStack - 1
-			tid := toStack - 1
-			// DEBUG
-			//fmt.Println("No chars:", n)
-			for i := 0; i < numBlocks; i++ {
-				// DEBUG
-				//fmt.Println("POP_FROM:", fid, "TO:", tid)
-				if v, ok := stacks[fid].Pop(); ok {
-					stacks[tid].Push(v)
-				}
-			}
 
-		} else {
-			cols := strings.Split(l, "")
-			// Check exit condition; a '1'
-			// If see '1'; is a border; last number is x * n
-			if cols[1] == "1" {
-				// DEBUG
-				//spew.Dump(queueSetup)
-				executeAction = true
-				stacks = make([]*arraystack.Stack, numOfStack)
-				// Load from Slice into Stacks ..
-				for sid, blocks := range queueSetup {
-					// DEBUG
-					//spew.Dump(blocks)
-					stacks[sid] = arraystack.New() // empty
-					// Reverse the order
-					for i := len(blocks) - 1; i >= 0; i-- {
-						// Push it into the stack
-						// DEBUG
-						//fmt.Println("PUSH:", blocks[i], "into Stack:", sid)
-						stacks[sid].Push(blocks[i])
-					}
-				}
-				// DEBUG - FULL STACK
-				//spew.Dump(stacks)
-				continue
-			}
-			// Setup one time at the start of the cycle
-			if numOfStack == 0 {
-				numOfStack = len(cols)/4 + 1
-				//fmt.Println("NO_STACK:", numOfStack)
-				queueSetup = make([][]string, numOfStack)
-			}
-			// extract + skip 4 everytime ..
-			//numOfStack = len(cols) % 4
-			//// DEBUG
-			////spew.Dump(cols)
-			//fmt.Println("COLS:", numOfStack)
-			// Extract out the block based on numOfStack
-			for i := 0; i < numOfStack; i++ {
-				cellContent := strings.TrimSpace(cols[i*4+1])
-				// DEBUG
-				// fmt.Print("BLOCK:", cellContent)
-				// Push into your own stack .. if NOT empty
-				if cellContent != "" {
-					// DEBUG
-					//fmt.Println("APPEND", cellContent, "into Slice", i)
-					//spew.Dump(queueSetup)
-					//if queueSetup[i] == nil {
-					//	// init make
-					//	queueSetup[i] = make([]string, 0)
-					//} else {
-					queueSetup[i] = append(queueSetup[i], cellContent)
-					//}
-				}
+	for i := 0; i < len(queueSetup); i++ {
+		if len(queueSetup[i]) > 0 {
+			fmt.Print(queueSetup[i][0])
+		}
+	}
+	return nil
+}
+
+// moveLine is one rearrangement step
+var moveLine = parse.MustTemplate("move", "move {n:int} from {from:int} to {to:int}")
+
+// parseDrawing splits the input into the starting stacks (top crate first)
+// and the list of moves
+func parseDrawing(data []string) ([][]string, []move, error) {
+	sections := parse.Sections(data)
+	if len(sections) != 2 {
+		return nil, nil, fmt.Errorf("want the drawing and the moves, got %d sections", len(sections))
+	}
+	drawing, steps := sections[0], sections[1]
+
+	// The last line of the drawing numbers the stacks
+	crates := drawing.Lines[:len(drawing.Lines)-1]
+	numOfStack := len(strings.Fields(drawing.Lines[len(drawing.Lines)-1]))
+	queueSetup := make([][]string, numOfStack)
+	for _, l := range crates {
+		cols := strings.Split(l, "")
+		// Extract out the block based on numOfStack
+		for i := 0; i < numOfStack && i*4+1 < len(cols); i++ {
+			cellContent := strings.TrimSpace(cols[i*4+1])
+			// Push into your own stack .. if NOT empty
+			if cellContent != "" {
+				queueSetup[i] = append(queueSetup[i], cellContent)
 			}
-			// DEBUG
-			//fmt.Println("=================>")
 		}
 	}
 
-	// From raw data; reverse = push into the stack
-	// Or from top now; from multiple, pick up the data
-	// and push there and then ..
-	// DEBUG
-	//spew.Dump(stacks)
+	var moves []move
+	for i, l := range steps.Lines {
+		m, err := moveLine.Match(l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", steps.Line+i, err)
+		}
+		moves = append(moves, move{m.Int("n"), m.Int("from") - 1, m.Int("to") - 1})
+	}
+	return queueSetup, moves, nil
+}
 
-	for i := 0; i < len(stacks); i++ {
-		if v, ok := stacks[i].Peek(); ok {
-			fmt.Print(v)
+// moveCrates applies m to the stacks in place; the CrateMover 9001 lifts all
+// the crates at once and keeps their order, the 9000 moves them one by one
+func moveCrates(queueSetup [][]string, m move, crateMover9001 bool) {
+	// The FROM acts as the starting; take existing and return as new existing ..
+	newTop := make([]string, m.numBlocks)
+	copy(newTop, queueSetup[m.fid][:m.numBlocks])
+	if !crateMover9001 {
+		// One at a time reverses the order of the picked up crates
+		for i, j := 0, len(newTop)-1; i < j; i, j = i+1, j-1 {
+			newTop[i], newTop[j] = newTop[j], newTop[i]
 		}
 	}
+	// Remove top from old Stack
+	if len(queueSetup[m.fid]) == m.numBlocks {
+		queueSetup[m.fid] = nil
+	} else {
+		queueSetup[m.fid] = queueSetup[m.fid][m.numBlocks:]
+	}
+	// New Stack
+	queueSetup[m.tid] = append(newTop, queueSetup[m.tid]...)
 }
 
-func Part2(filePath string) {
+// Visualize replays the rearrangement in filePath through anim, one step per
+// move; the crates that just moved are highlighted
+func Visualize(filePath string, crateMover9001 bool, anim *viz.Animator) error {
 	data, err := script.File(filePath).Slice()
 	if err != nil {
-		panic(err)
+		return err
 	}
-	numOfStack := 0
-	executeAction := false
-	var queueSetup [][]string
-	//var stacks []*arraystack.Stack
-	for _, l := range data {
-		if executeAction {
-			// DEBUG
-			//spew.Dump(queueSetup)
-			var numBlocks, fromStack, toStack int
-			// Now in the instructions mode
-			_, err := fmt.Sscanf(l, "move %d from %d to %d", &numBlocks, &fromStack, &toStack)
-			if err != nil {
-				//panic(err)
-				continue
-			}
-			fid := fromStack - 1
-			tid := toStack - 1
-			// DEBUG
-			//fmt.Println("MOVE:", numBlocks, "FROM_ID:", fid, "TO_ID:", tid)
-			// DEBUG
-			//fmt.Println("BEFORE")
-			//spew.Dump(queueSetup[fid])
-			//spew.Dump(queueSetup[tid])
-			// Remove numBlocks element from FID
-			// The FROM acts as the starting; take existing and return as new existing ..
-			newTop := make([]string, numBlocks)
-			copy(newTop, queueSetup[fid][:numBlocks])
-			// Remove top from old Stack
-			if len(queueSetup[fid]) == numBlocks {
-				queueSetup[fid] = nil
-			} else {
-				queueSetup[fid] = queueSetup[fid][numBlocks:]
-			}
-			// DEBUG
-			//spew.Dump(newTop)
-			// New Stack
-			queueSetup[tid] = append(newTop, queueSetup[tid]...)
-			// DEBUG
-			//fmt.Println("AFTER")
-			//spew.Dump(queueSetup[fid])
-			//spew.Dump(queueSetup[tid])
-		} else {
-			cols := strings.Split(l, "")
-			// Check exit condition; a '1'
-			// If see '1'; is a border; last number is x * n
-			if cols[1] == "1" {
-				// DEBUG
-				//spew.Dump(queueSetup)
-				executeAction = true
-				continue
-			}
-			// Setup one time at the start of the cycle
-			if numOfStack == 0 {
-				numOfStack = len(cols)/4 + 1
-				//fmt.Println("NO_STACK:", numOfStack)
-				queueSetup = make([][]string, numOfStack)
-			}
-			// Extract out the block based on numOfStack
-			for i := 0; i < numOfStack; i++ {
-				cellContent := strings.TrimSpace(cols[i*4+1])
-				// DEBUG
-				// fmt.Print("BLOCK:", cellContent)
-				// Push into your own stack .. if NOT empty
-				if cellContent != "" {
-					// DEBUG
-					//fmt.Println("APPEND", cellContent, "into Slice", i)
-					//spew.Dump(queueSetup)
-					//if queueSetup[i] == nil {
-					//	// init make
-					//	queueSetup[i] = make([]string, 0)
-					//} else {
-					queueSetup[i] = append(queueSetup[i], cellContent)
-					//}
-				}
-			}
-		}
+	queueSetup, moves, err := parseDrawing(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
 	}
-
-	for i := 0; i < len(queueSetup); i++ {
-		fmt.Print(queueSetup[i][0])
+	if err := anim.Start("start", viz.Stacks(queueSetup, nil, anim.Emphasis())); err != nil {
+		return err
+	}
+	var title, frame string
+	for _, m := range moves {
+		moveCrates(queueSetup, m, crateMover9001)
+		hot := func(stack, depth int) bool {
+			return stack == m.tid && depth < m.numBlocks
+		}
+		title = fmt.Sprintf("move %d from %d to %d", m.numBlocks, m.fid+1, m.tid+1)
+		frame = viz.Stacks(queueSetup, hot, anim.Emphasis())
+		if err := anim.Step(title, frame); err != nil {
+			return err
+		}
 	}
+	return anim.Done(title, frame)
 }
@@ -0,0 +1,111 @@
+package day05
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aoc/pkg/viz"
+)
+
+func TestVisualize(t *testing.T) {
+	tests := []struct {
+		name           string
+		crateMover9001 bool
+		golden         string
+	}{
+		{"CrateMover 9000", false, "testdata/sample-frames-9000.txt"},
+		{"CrateMover 9001", true, "testdata/sample-frames-9001.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := os.ReadFile(tt.golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got bytes.Buffer
+			if err := Visualize("testdata/sample.txt", tt.crateMover9001, viz.NewHeadless(&got, viz.Options{})); err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != string(want) {
+				t.Errorf("Visualize() frames =\n%s\nwant\n%s", got.String(), want)
+			}
+		})
+	}
+}
+
+// captureStdout returns what f prints
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	f()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+// writeInput writes input to a file in a fresh temporary directory
+func writeInput(t *testing.T, input string) string {
+	t.Helper()
+	filePath := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(filePath, []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filePath
+}
+
+// TestPartsTrimmedDrawing runs the sample with the trailing spaces of the
+// drawing trimmed, as editors tend to leave it
+func TestPartsTrimmedDrawing(t *testing.T) {
+	sample, err := os.ReadFile("testdata/sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, l := range strings.Split(string(sample), "\n") {
+		lines = append(lines, strings.TrimRight(l, " "))
+	}
+	filePath := writeInput(t, strings.Join(lines, "\n"))
+	for _, tt := range []struct {
+		name string
+		part func(string) error
+		want string
+	}{
+		{"Part1", Part1, "CMZ"},
+		{"Part2", Part2, "MCD"},
+	} {
+		var err error
+		if got := captureStdout(t, func() { err = tt.part(filePath) }); err != nil || got != tt.want {
+			t.Errorf("%s = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+// TestPartsRejectBadMoves checks that a line in the moves that is not a
+// move stops the rearrangement with its line number
+func TestPartsRejectBadMoves(t *testing.T) {
+	sample, err := os.ReadFile("testdata/sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filePath := writeInput(t, string(sample)+"move some crates\nmove 1 from 3 to 1\n")
+	for name, part := range map[string]func(string) error{"Part1": Part1, "Part2": Part2} {
+		var err error
+		captureStdout(t, func() { err = part(filePath) })
+		if err == nil || !strings.Contains(err.Error(), "line 10:") {
+			t.Errorf("%s error = %v, want one on line 10", name, err)
+		}
+	}
+}
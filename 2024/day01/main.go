@@ -1,16 +1,160 @@
 package main
 
-import "fmt"
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
 
 func main() {
-  fmt.Println("AOC 2024 Day 01 ..")
+	fmt.Println("AOC 2024 Day 01 ..")
 
-  // Helper file read content by line
+	left, right, err := readLists("data.txt")
+	if err != nil {
+		panic(err)
+	}
 
-  // Sort col2
+	// Sort both columns; pairs are then smallest-with-smallest
+	slices.Sort(left)
+	slices.Sort(right)
 
-  // Naive loop; accumulate total
+	fmt.Println("Part 1 - Total distance:", totalDistanceParallel(left, right, runtime.NumCPU()))
+	fmt.Println("Part 2 - Similarity score:", similarityScoreParallel(left, right, runtime.NumCPU()))
+}
+
+// readLists loads the two location ID columns
+func readLists(filename string) (left, right []int, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, nil, fmt.Errorf("line %d: want 2 columns, got %d", lineNo, len(fields))
+		}
+		l, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		r, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		left = append(left, l)
+		right = append(right, r)
+	}
+	return left, right, scanner.Err()
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// totalDistance is the naive loop; both lists must already be sorted
+func totalDistance(left, right []int) int {
+	total := 0
+	for i := range left {
+		total += abs(left[i] - right[i])
+	}
+	return total
+}
+
+// totalDistanceParallel maps chunks of the sorted pairs onto workers and
+// reduces their partial sums
+func totalDistanceParallel(left, right []int, workers int) int {
+	partials := mapChunks(len(left), workers, func(lo, hi int) int {
+		return totalDistance(left[lo:hi], right[lo:hi])
+	})
+	total := 0
+	for _, p := range partials {
+		total += p
+	}
+	return total
+}
+
+// similarityScore adds up each left number times how often it appears in
+// the right list
+func similarityScore(left, right []int) int {
+	freq := make(map[int]int, len(right))
+	for _, r := range right {
+		freq[r]++
+	}
+	score := 0
+	for _, l := range left {
+		score += l * freq[l]
+	}
+	return score
+}
+
+// similarityScoreParallel counts the right list in chunks, merges the
+// frequency tables, then scores chunks of the left list against it
+func similarityScoreParallel(left, right []int, workers int) int {
+	counts := mapChunks(len(right), workers, func(lo, hi int) map[int]int {
+		freq := make(map[int]int, hi-lo)
+		for _, r := range right[lo:hi] {
+			freq[r]++
+		}
+		return freq
+	})
+	freq := make(map[int]int, len(right))
+	for _, c := range counts {
+		for k, v := range c {
+			freq[k] += v
+		}
+	}
+
+	partials := mapChunks(len(left), workers, func(lo, hi int) int {
+		score := 0
+		for _, l := range left[lo:hi] {
+			score += l * freq[l]
+		}
+		return score
+	})
+	score := 0
+	for _, p := range partials {
+		score += p
+	}
+	return score
+}
 
-  // NOTE: Perfectly parallable .. batch to number of CPUs ..
-  // Map / Reduce ..
+// mapChunks splits [0, n) into at most workers contiguous chunks, runs fn on
+// each chunk in its own goroutine and returns the per chunk results
+func mapChunks[T any](n, workers int, fn func(lo, hi int) T) []T {
+	if workers < 1 {
+		workers = 1
+	}
+	chunkSize := (n + workers - 1) / workers
+	if chunkSize == 0 {
+		return nil
+	}
+	results := make([]T, (n+chunkSize-1)/chunkSize)
+	var wg sync.WaitGroup
+	for w := range results {
+		lo := w * chunkSize
+		hi := min(lo+chunkSize, n)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[w] = fn(lo, hi)
+		}()
+	}
+	wg.Wait()
+	return results
 }
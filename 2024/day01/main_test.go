@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// Sample from the puzzle description
+var (
+	sampleLeft  = []int{3, 4, 2, 1, 3, 3}
+	sampleRight = []int{4, 3, 5, 3, 9, 3}
+)
+
+func sortedSample() ([]int, []int) {
+	left, right := slices.Clone(sampleLeft), slices.Clone(sampleRight)
+	slices.Sort(left)
+	slices.Sort(right)
+	return left, right
+}
+
+func TestTotalDistance(t *testing.T) {
+	left, right := sortedSample()
+	if got := totalDistance(left, right); got != 11 {
+		t.Errorf("totalDistance() = %d, want 11", got)
+	}
+	for _, workers := range []int{1, 2, 4, 16} {
+		if got := totalDistanceParallel(left, right, workers); got != 11 {
+			t.Errorf("totalDistanceParallel(workers=%d) = %d, want 11", workers, got)
+		}
+	}
+}
+
+func TestSimilarityScore(t *testing.T) {
+	left, right := sortedSample()
+	if got := similarityScore(left, right); got != 31 {
+		t.Errorf("similarityScore() = %d, want 31", got)
+	}
+	for _, workers := range []int{1, 2, 4, 16} {
+		if got := similarityScoreParallel(left, right, workers); got != 31 {
+			t.Errorf("similarityScoreParallel(workers=%d) = %d, want 31", workers, got)
+		}
+	}
+}
+
+func TestParallelMatchesSequential(t *testing.T) {
+	left, right := randomLists(10_000, 1)
+	if got, want := totalDistanceParallel(left, right, runtime.NumCPU()), totalDistance(left, right); got != want {
+		t.Errorf("totalDistanceParallel() = %d, sequential = %d", got, want)
+	}
+	if got, want := similarityScoreParallel(left, right, runtime.NumCPU()), similarityScore(left, right); got != want {
+		t.Errorf("similarityScoreParallel() = %d, sequential = %d", got, want)
+	}
+}
+
+func TestReadLists(t *testing.T) {
+	left, right, err := readLists("data.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 1000 || len(right) != 1000 {
+		t.Errorf("readLists() got %d/%d entries, want 1000/1000", len(left), len(right))
+	}
+	if left[0] != 80784 || right[0] != 47731 {
+		t.Errorf("readLists() first pair = %d,%d, want 80784,47731", left[0], right[0])
+	}
+}
+
+func randomLists(n int, seed int64) ([]int, []int) {
+	rng := rand.New(rand.NewSource(seed))
+	left, right := make([]int, n), make([]int, n)
+	for i := range left {
+		left[i] = rng.Intn(100_000)
+		right[i] = rng.Intn(100_000)
+	}
+	slices.Sort(left)
+	slices.Sort(right)
+	return left, right
+}
+
+// Run with -bench . to see at which list size the map/reduce path starts
+// to beat the plain loop on this machine
+func BenchmarkTotalDistance(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000, 1_000_000} {
+		left, right := randomLists(n, 1)
+		b.Run(fmt.Sprintf("sequential/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				totalDistance(left, right)
+			}
+		})
+		b.Run(fmt.Sprintf("parallel/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				totalDistanceParallel(left, right, runtime.NumCPU())
+			}
+		})
+	}
+}
+
+func BenchmarkSimilarityScore(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000, 1_000_000} {
+		left, right := randomLists(n, 1)
+		b.Run(fmt.Sprintf("sequential/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				similarityScore(left, right)
+			}
+		})
+		b.Run(fmt.Sprintf("parallel/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				similarityScoreParallel(left, right, runtime.NumCPU())
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bitfield/script"
+
+	"aoc/pkg/intmath"
+	"aoc/pkg/parse"
+)
+
+// Dial is a safe dial numbered 0 to Size-1 that keeps count of how often
+// each watched position is landed on (hit) or clicked through (passed)
+type Dial struct {
+	Size     int
+	Position int
+	hits     map[int]int
+	passes   map[int]int
+}
+
+// NewDial creates a dial of size positions pointing at start, watching the
+// given positions
+func NewDial(size, start int, watched ...int) (*Dial, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("dial size must be positive, got %d", size)
+	}
+	if start < 0 || start >= size {
+		return nil, fmt.Errorf("start %d is not on a dial of size %d", start, size)
+	}
+	d := &Dial{
+		Size:     size,
+		Position: start,
+		hits:     make(map[int]int, len(watched)),
+		passes:   make(map[int]int, len(watched)),
+	}
+	for _, p := range watched {
+		if p < 0 || p >= size {
+			return nil, fmt.Errorf("watched position %d is not on a dial of size %d", p, size)
+		}
+		d.hits[p] = 0
+		d.passes[p] = 0
+	}
+	return d, nil
+}
+
+// Rotate turns the dial by delta clicks, negative being 'L', and updates
+// the counters of every watched position
+func (d *Dial) Rotate(delta int) {
+	for p := range d.hits {
+		// Measure from p so that p is the 0 that turn counts
+		_, zeros := turn(d.Position-p, delta, d.Size)
+		landed := 0
+		if delta != 0 && intmath.Mod(d.Position+delta, d.Size) == p {
+			landed = 1
+		}
+		d.hits[p] += landed
+		d.passes[p] += zeros - landed
+	}
+	d.Position = intmath.Mod(d.Position+delta, d.Size)
+}
+
+// Hits is how many rotations ended on p; turning by 0 clicks is not one
+func (d *Dial) Hits(p int) int {
+	return d.hits[p]
+}
+
+// Passes is how many clicks went through p without the rotation ending there
+func (d *Dial) Passes(p int) int {
+	return d.passes[p]
+}
+
+// Clicks is every click that left the dial pointing at p
+func (d *Dial) Clicks(p int) int {
+	return d.hits[p] + d.passes[p]
+}
+
+// RotateAll applies every rotation in the input file to the dial; trace,
+// when not nil, gets a record of each one
+func (d *Dial) RotateAll(input string, trace Tracer) error {
+	lines, err := script.File(input).Slice()
+	if err != nil {
+		return err
+	}
+	count := 0
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		delta, err := parseRotation(line)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", input, i+1, err)
+		}
+		before := d.Position
+		d.Rotate(delta)
+		if trace == nil {
+			continue
+		}
+		_, zeros := turn(before, delta, d.Size)
+		count += zeros
+		rec := TraceRecord{
+			Line:     i + 1,
+			Rotation: line,
+			Before:   before,
+			After:    d.Position,
+			Zeros:    zeros,
+			Count:    count,
+		}
+		if err := trace(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotation is a turn to the left or right by some clicks
+var rotation = parse.Matcher{
+	parse.MustTemplate("L", "L{clicks:int}"),
+	parse.MustTemplate("R", "R{clicks:int}"),
+}
+
+// parseRotation turns "L68" into -68 and "R48" into 48
+func parseRotation(line string) (int, error) {
+	m, err := rotation.Match(line)
+	if err != nil {
+		return 0, fmt.Errorf("bad rotation: %w", err)
+	}
+	if m.Template == "L" {
+		return -m.Int("clicks"), nil
+	}
+	return m.Int("clicks"), nil
+}
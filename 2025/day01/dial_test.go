@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"aoc/pkg/intmath"
+)
+
+func TestNewDial(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		start   int
+		watched []int
+		wantErr bool
+	}{
+		{"puzzle dial", 100, 50, []int{0}, false},
+		{"no watched positions", 10, 0, nil, false},
+		{"zero size", 0, 0, nil, true},
+		{"start off the dial", 10, 10, nil, true},
+		{"watched off the dial", 10, 5, []int{-1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDial(tt.size, tt.start, tt.watched...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewDial() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDialRotateAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantHits   int
+		wantClicks int
+	}{
+		{"sample", "part2.txt", 3, 6},
+		{"big turns", "part2a.txt", 0, 17},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDial(100, 50, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := d.RotateAll(tt.input, nil); err != nil {
+				t.Fatal(err)
+			}
+			if d.Hits(0) != tt.wantHits || d.Clicks(0) != tt.wantClicks {
+				t.Errorf("Hits(0) = %d, Clicks(0) = %d, want %d, %d",
+					d.Hits(0), d.Clicks(0), tt.wantHits, tt.wantClicks)
+			}
+		})
+	}
+}
+
+func TestDialWatchedPositions(t *testing.T) {
+	// 0-9 dial from 5: R7 passes 6..9,0,1 and lands on 2; L4 passes 1,0,9 and lands on 8
+	d, err := NewDial(10, 5, 0, 2, 8, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Rotate(7)
+	d.Rotate(-4)
+	tests := []struct {
+		p          int
+		wantHits   int
+		wantPasses int
+	}{
+		{0, 0, 2},
+		{2, 1, 0},
+		{8, 1, 1},
+		{5, 0, 0},
+	}
+	for _, tt := range tests {
+		if d.Hits(tt.p) != tt.wantHits || d.Passes(tt.p) != tt.wantPasses {
+			t.Errorf("position %d: hits %d, passes %d, want %d, %d",
+				tt.p, d.Hits(tt.p), d.Passes(tt.p), tt.wantHits, tt.wantPasses)
+		}
+	}
+}
+
+// TestDialMatchesClicks compares the counters with a click by click walk on
+// random dials and rotations
+func TestDialMatchesClicks(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	for round := 0; round < 200; round++ {
+		size := rng.Intn(20) + 1
+		start := rng.Intn(size)
+		watched := []int{rng.Intn(size), rng.Intn(size)}
+		d, err := NewDial(size, start, watched...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pos := start
+		hits := make(map[int]int)
+		passes := make(map[int]int)
+		for i := 0; i < 50; i++ {
+			delta := rng.Intn(6*size+1) - 3*size
+			d.Rotate(delta)
+			step := 1
+			if delta < 0 {
+				step = -1
+			}
+			for c := 1; c <= intmath.Abs(delta); c++ {
+				pos = intmath.Mod(pos+step, size)
+				if c == intmath.Abs(delta) {
+					hits[pos]++
+				} else {
+					passes[pos]++
+				}
+			}
+		}
+		for _, p := range watched {
+			if d.Hits(p) != hits[p] || d.Passes(p) != passes[p] {
+				t.Fatalf("size %d start %d position %d: hits %d, passes %d, want %d, %d",
+					size, start, p, d.Hits(p), d.Passes(p), hits[p], passes[p])
+			}
+		}
+	}
+}
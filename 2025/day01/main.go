@@ -1,114 +1,93 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"strconv"
+	"os"
 
-	"github.com/bitfield/script"
-	"github.com/davecgh/go-spew/spew"
+	"aoc/pkg/intmath"
 )
 
 func main() {
+	input := flag.String("input", "input.txt", "puzzle input")
+	traceFile := flag.String("trace", "", "write one JSON line per rotation to this file ('-' for stdout)")
+	flag.Parse()
 
 	fmt.Println("Welcome to AOC 2025 Day01!!")
-	// Always starts at 50; with dial from 0 - 99 (100 items)
-	//part1("input.txt")
-	part2("input.txt")
-}
 
-func part1(input string) {
-	// If action is 'L'; then compare if eq, less, more ..
-	s, serr := script.File(input).FilterLine(func(s string) string {
-		// Split command; Left is negative
-		if s[0] == 'L' {
-			s = "-" + s[1:]
-		} else {
-			s = "+" + s[1:]
+	var trace Tracer
+	switch *traceFile {
+	case "":
+	case "-":
+		trace = JSONLTracer(os.Stdout)
+	default:
+		f, err := os.Create(*traceFile)
+		if err != nil {
+			panic(err)
 		}
-		return s
-	}).Slice()
-	if serr != nil {
-		panic(serr)
+		defer f.Close()
+		trace = JSONLTracer(f)
 	}
 
-	// Find out how far from the edge; start of 50; it is 44 from edge both side
-	// howFarFromHundred ...
-	spew.Dump(s)
-
-	count := 0
-	current := 50
-	// Rule is simpler .. no need modulus
-	// If it reaches exactly 0; the increase the counter
-	for _, action := range s {
-		fmt.Println(action)
+	// Always starts at 50; with dial from 0 - 99 (100 items)
+	fmt.Println("Zero hits:", part1(*input))
+	fmt.Println("Final count:", part2(*input, trace))
+}
 
-		step, cerr := strconv.Atoi(action)
-		if cerr != nil {
-			panic(cerr)
-		}
-		current += step
-		current = current % 100
-		fmt.Println("Current after step: ", current)
-		if current == 0 {
-			count++
-		}
+// part1 counts the rotations that leave the dial pointing at 0
+func part1(input string) int {
+	dial, err := NewDial(100, 50, 0)
+	if err != nil {
+		panic(err)
 	}
-	fmt.Println("Count: ", count)
-	// Below might be a more sophisitcated one .,
-	// Do nothing; continue
-	// If current + action < 100 and > 0
-	//      continue ..
-	// Special cases (increase counter):
-	// If current + action == 100
-	// If current + action == 0
-	// If current + action > 100
-	//		left over modules 100?
-	//		left over divide 100 += counter
-	// If current + action < 0
-	//		left over modules 0?
-	//		left over divide 100 += counter
-
+	if err := dial.RotateAll(input, nil); err != nil {
+		panic(err)
+	}
+	return dial.Hits(0)
 }
 
-func part2(input string) {
-	lines, err := script.File(input).Slice()
+func part2(input string, trace Tracer) int {
+	dial, err := NewDial(100, 50, 0)
 	if err != nil {
 		panic(err)
 	}
+	if err := dial.RotateAll(input, trace); err != nil {
+		panic(err)
+	}
+	return dial.Clicks(0)
+}
 
-	count := 0
-	current := 50
-
-	for _, line := range lines {
-		if len(line) < 2 {
-			continue
-		}
-
-		direction := line[0]
-		clicksStr := line[1:]
-		clicks, err := strconv.Atoi(clicksStr)
-		if err != nil {
-			panic(err)
-		}
-
-		step := 1
-		if direction == 'L' {
-			step = -1
-		}
+// rotate turns the dial (0 - 99) from current by delta clicks, negative being
+// 'L', in O(1) instead of click by click. It returns the new position and
+// how many clicks left the dial pointing at 0, including the final one.
+func rotate(current, delta int) (next, zeros int) {
+	return turn(current, delta, 100)
+}
 
-		fmt.Printf("Starting rotation: %s, current=%d, clicks=%d, step=%d\n", line, current, clicks, step)
+// turn is rotate for a dial of any size.
+//
+// Going right, 0 is hit each time current+delta crosses a multiple of size.
+// Going left, shift by one so that landing on 0 counts but starting from it
+// does not: those are the multiples of size in [current+delta, current-1].
+func turn(current, delta, size int) (next, zeros int) {
+	next = intmath.Mod(current+delta, size)
+	if delta >= 0 {
+		return next, intmath.FloorDiv(current+delta, size) - intmath.FloorDiv(current, size)
+	}
+	return next, intmath.FloorDiv(current-1, size) - intmath.FloorDiv(current+delta-1, size)
+}
 
-		// Simulate each click
-		for i := 0; i < clicks; i++ {
-			current = ((current+step)%100 + 100) % 100
-			if current == 0 {
-				count++
-				fmt.Printf("  Click %d: current=%d, count=%d\n", i+1, current, count)
-			}
+// rotateByClicks is the straightforward simulation rotate must agree with
+func rotateByClicks(current, delta int) (next, zeros int) {
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	for i := 0; i < intmath.Abs(delta); i++ {
+		current = intmath.Mod(current+step, 100)
+		if current == 0 {
+			zeros++
 		}
-
-		fmt.Printf("After rotation: current=%d\n", current)
 	}
-
-	fmt.Println("Final count:", count)
+	return current, zeros
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -11,12 +12,16 @@ func Test_part1(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
+		want int
 	}{
-		{"happy", args{"test.txt"}},
+		{"happy", args{"test.txt"}, 1},
+		{"sample", args{"part2.txt"}, 3},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			part1(tt.args.input)
+			if got := part1(tt.args.input); got != tt.want {
+				t.Errorf("part1() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -29,13 +34,63 @@ func Test_part2(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
+		want int
 	}{
-		{"happy", args{"part2.txt"}},
-		{"happy2", args{"part2a.txt"}},
+		{"happy", args{"part2.txt"}, 6},
+		{"happy2", args{"part2a.txt"}, 17},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			part2(tt.args.input)
+			if got := part2(tt.args.input, nil); got != tt.want {
+				t.Errorf("part2() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
+
+func Test_rotate(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   int
+		delta     int
+		wantNext  int
+		wantZeros int
+	}{
+		{"no move", 50, 0, 50, 0},
+		{"right to exactly 0", 50, 50, 0, 1},
+		{"right past 0", 50, 60, 10, 1},
+		{"right full turns", 50, 1000, 50, 10},
+		{"left to exactly 0", 50, -50, 0, 1},
+		{"left past 0", 50, -68, 82, 1},
+		{"left from 0 does not count start", 0, -5, 95, 0},
+		{"left from 0 full turn", 0, -100, 0, 1},
+		{"right from 0 full turn", 0, 100, 0, 1},
+		{"left full turns", 50, -1000, 50, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotNext, gotZeros := rotate(tt.current, tt.delta)
+			if gotNext != tt.wantNext || gotZeros != tt.wantZeros {
+				t.Errorf("rotate(%d, %d) = %d, %d, want %d, %d",
+					tt.current, tt.delta, gotNext, gotZeros, tt.wantNext, tt.wantZeros)
+			}
+		})
+	}
+}
+
+// Test_rotateMatchesClicks checks the closed form against the click by click
+// simulation on random instructions
+func Test_rotateMatchesClicks(t *testing.T) {
+	rng := rand.New(rand.NewSource(2025))
+	current, simulated := 50, 50
+	for i := 0; i < 10000; i++ {
+		delta := rng.Intn(1001) - 500
+		next, zeros := rotate(current, delta)
+		wantNext, wantZeros := rotateByClicks(simulated, delta)
+		if next != wantNext || zeros != wantZeros {
+			t.Fatalf("rotate(%d, %d) = %d, %d, simulation gives %d, %d",
+				current, delta, next, zeros, wantNext, wantZeros)
+		}
+		current, simulated = next, wantNext
+	}
+}
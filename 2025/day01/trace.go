@@ -0,0 +1,27 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// TraceRecord is what one rotation did to the dial
+type TraceRecord struct {
+	Line     int    `json:"line"`     // line number in the input file
+	Rotation string `json:"rotation"` // the instruction as written, e.g. "L68"
+	Before   int    `json:"before"`   // position before the rotation
+	After    int    `json:"after"`    // position after the rotation
+	Zeros    int    `json:"zeros"`    // clicks in this rotation that pointed at 0
+	Count    int    `json:"count"`    // running total of Zeros
+}
+
+// Tracer receives a TraceRecord after every rotation
+type Tracer func(TraceRecord) error
+
+// JSONLTracer writes each record as one line of JSON
+func JSONLTracer(w io.Writer) Tracer {
+	enc := json.NewEncoder(w)
+	return func(r TraceRecord) error {
+		return enc.Encode(r)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONLTracer(t *testing.T) {
+	var buf bytes.Buffer
+	if got := part2("part2.txt", JSONLTracer(&buf)); got != 6 {
+		t.Fatalf("part2() = %d, want 6", got)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("got %d trace lines, want 10", len(lines))
+	}
+
+	var first TraceRecord
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	want := TraceRecord{Line: 1, Rotation: "L68", Before: 50, After: 82, Zeros: 1, Count: 1}
+	if first != want {
+		t.Errorf("first record = %+v, want %+v", first, want)
+	}
+
+	// Records chain: each starts where the previous one ended
+	var last TraceRecord
+	for i, l := range lines {
+		var rec TraceRecord
+		if err := json.Unmarshal([]byte(l), &rec); err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && rec.Before != last.After {
+			t.Errorf("line %d: before = %d, previous after = %d", rec.Line, rec.Before, last.After)
+		}
+		if rec.Count != last.Count+rec.Zeros {
+			t.Errorf("line %d: count = %d, want %d", rec.Line, rec.Count, last.Count+rec.Zeros)
+		}
+		last = rec
+	}
+	if last.Count != 6 {
+		t.Errorf("final count = %d, want 6", last.Count)
+	}
+}
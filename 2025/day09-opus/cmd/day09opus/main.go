@@ -0,0 +1,26 @@
+// Command day09opus runs the slab solution to 2025 day 09 part 2 on its own,
+// without the repository runner
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	day09opus "aoc/2025/day09-opus"
+)
+
+func main() {
+	input := flag.String("input", "-", "puzzle input file, or - for stdin")
+	workers := flag.Int("workers", 0, "search goroutines (0 uses every CPU)")
+	timing := flag.Bool("timing", true, "print how long each phase took")
+	flag.Parse()
+
+	answer, err := day09opus.Solve(context.Background(), day09opus.Options{Input: *input, Workers: *workers, Timing: *timing})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("\n=== Answer (Part 2): %d ===\n", answer)
+}
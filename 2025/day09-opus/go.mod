@@ -1,3 +0,0 @@
-module aoc-day9
-
-go 1.25.5
@@ -1,215 +1,126 @@
-package main
+package day09opus
 
 import (
-	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
-	"sort"
-	"strconv"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
-)
 
-// Point represents a 2D coordinate
-type Point struct {
-	X, Y int
-}
+	"aoc/pkg/geometry"
+	"aoc/pkg/progress"
+)
 
-// Interval represents a horizontal interval [Left, Right]
-type Interval struct {
-	Left, Right int
-}
+// Point, Polygon and the slab decomposition come from the shared geometry package
+type (
+	Point   = geometry.Point
+	Polygon = geometry.RectilinearPolygon
+)
 
-// HorizontalSlab represents a horizontal strip with y in [YMin, YMax)
-type HorizontalSlab struct {
-	YMin, YMax    int
-	InsideXRanges []Interval // sorted, non-overlapping intervals
+// Options configure Solve
+type Options struct {
+	Input    string        // puzzle input file, or "-" for stdin
+	Workers  int           // search goroutines; 0 uses every CPU
+	Timing   bool          // print how long each phase took
+	Out      io.Writer     // where timings go; nil means stdout
+	Progress progress.Func // optional, see LargestRectangleContext
 }
 
-// Polygon holds the rectilinear polygon data
-type Polygon struct {
-	Vertices []Point
-	Slabs    []HorizontalSlab // sorted by YMin
-	YCoords  []int            // sorted unique Y coordinates
+// Run solves Part 2 for the puzzle input in inputFile on every CPU
+func Run(inputFile string) int64 {
+	answer, err := Solve(context.Background(), Options{Input: inputFile})
+	if err != nil {
+		panic(err)
+	}
+	return answer
 }
 
-func main() {
-	runtime.GOMAXPROCS(16)
+// Solve reads the polygon named by opts.Input and searches it for the
+// largest rectangle, stopping early when ctx is done
+func Solve(ctx context.Context, opts Options) (int64, error) {
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
 	start := time.Now()
 
 	// Parse input
-	redTiles := parseInput("/tmp/aoc-day9/input.txt")
-	fmt.Printf("Parsed %d red tiles (polygon vertices)\n", len(redTiles))
+	redTiles, err := readPolygon(opts.Input)
+	if err != nil {
+		return 0, err
+	}
 
 	// Build polygon structure
-	polygon := buildPolygon(redTiles)
-	fmt.Printf("Built polygon with %d horizontal slabs\n", len(polygon.Slabs))
-	fmt.Printf("Polygon build time: %v\n", time.Since(start))
+	polygon := geometry.NewRectilinearPolygon(redTiles)
+	if opts.Timing {
+		fmt.Fprintf(out, "Parsed %d red tiles (polygon vertices)\n", len(redTiles))
+		fmt.Fprintf(out, "Built polygon with %d horizontal slabs\n", len(polygon.Slabs))
+		fmt.Fprintf(out, "Polygon build time: %v\n", time.Since(start))
+	}
 
 	// Find largest valid rectangle using concurrent search
 	searchStart := time.Now()
-	answer := findLargestRectangleConcurrent(redTiles, polygon, 16)
-	fmt.Printf("Search time: %v\n", time.Since(searchStart))
-	fmt.Printf("Total time: %v\n", time.Since(start))
-	fmt.Printf("\n=== Answer (Part 2): %d ===\n", answer)
+	answer, err := searchRectangles(ctx, redTiles, polygon, workers, opts.Progress)
+	if opts.Timing {
+		fmt.Fprintf(out, "Search time (%d workers): %v\n", workers, time.Since(searchStart))
+		fmt.Fprintf(out, "Total time: %v\n", time.Since(start))
+	}
+	return int64(answer), err
+}
+
+// readPolygon reads and validates the red tiles from a file or stdin
+func readPolygon(input string) ([]Point, error) {
+	if input == "-" {
+		points, _, err := geometry.ParsePolygon(os.Stdin)
+		return points, err
+	}
+	points, _, err := geometry.ReadPolygon(input)
+	return points, err
 }
 
 func parseInput(filename string) []Point {
-	file, err := os.Open(filename)
+	points, err := geometry.ReadPoints(filename)
 	if err != nil {
 		panic(err)
 	}
-	defer file.Close()
-
-	var points []Point
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-		parts := strings.Split(line, ",")
-		x, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
-		y, _ := strconv.Atoi(strings.TrimSpace(parts[1]))
-		points = append(points, Point{X: x, Y: y})
-	}
 	return points
 }
 
-// buildPolygon constructs the slab decomposition for efficient containment queries
-func buildPolygon(vertices []Point) *Polygon {
-	n := len(vertices)
-	if n == 0 {
-		return &Polygon{}
-	}
-
-	// Collect all unique Y coordinates
-	ySet := make(map[int]bool)
-	for _, v := range vertices {
-		ySet[v.Y] = true
-	}
-
-	yCoords := make([]int, 0, len(ySet))
-	for y := range ySet {
-		yCoords = append(yCoords, y)
-	}
-	sort.Ints(yCoords)
-
-	// Build vertical edges of the polygon
-	type VerticalEdge struct {
-		X          int
-		YMin, YMax int
-	}
-
-	var verticalEdges []VerticalEdge
-	for i := 0; i < n; i++ {
-		p1 := vertices[i]
-		p2 := vertices[(i+1)%n]
-
-		if p1.X == p2.X {
-			// Vertical edge
-			yMin, yMax := p1.Y, p2.Y
-			if yMin > yMax {
-				yMin, yMax = yMax, yMin
-			}
-			verticalEdges = append(verticalEdges, VerticalEdge{X: p1.X, YMin: yMin, YMax: yMax})
-		}
-	}
-
-	// Build slabs using scanline
-	slabs := make([]HorizontalSlab, 0, len(yCoords)-1)
-
-	for i := 0; i < len(yCoords)-1; i++ {
-		yMin := yCoords[i]
-		yMax := yCoords[i+1]
-
-		// Use midpoint for crossing test (avoids boundary issues)
-		yMid := float64(yMin+yMax) / 2.0
-
-		// Find all vertical edges that cross through this slab's interior
-		var crossingX []int
-		for _, edge := range verticalEdges {
-			// Edge crosses the slab if its Y range strictly contains the midpoint
-			if float64(edge.YMin) < yMid && float64(edge.YMax) > yMid {
-				crossingX = append(crossingX, edge.X)
-			}
-		}
-		sort.Ints(crossingX)
-
-		// Pair up crossings to form inside intervals (scanline fill)
-		// Going left-to-right, we toggle in/out at each crossing
-		var insideRanges []Interval
-		for j := 0; j+1 < len(crossingX); j += 2 {
-			insideRanges = append(insideRanges, Interval{
-				Left:  crossingX[j],
-				Right: crossingX[j+1],
-			})
-		}
-
-		slabs = append(slabs, HorizontalSlab{
-			YMin:          yMin,
-			YMax:          yMax,
-			InsideXRanges: insideRanges,
-		})
-	}
-
-	return &Polygon{
-		Vertices: vertices,
-		Slabs:    slabs,
-		YCoords:  yCoords,
-	}
+// LargestRectangle is the largest rectangle with red corners that only
+// covers red or green tiles, searched by numWorkers goroutines
+func LargestRectangle(redTiles []Point, numWorkers int) int64 {
+	polygon := geometry.NewRectilinearPolygon(redTiles)
+	return int64(findLargestRectangleConcurrent(redTiles, polygon, numWorkers))
 }
 
-// isRectangleValid checks if a rectangle with opposite corners (x1,y1) and (x2,y2)
-// is fully contained within the polygon
-func (p *Polygon) isRectangleValid(x1, y1, x2, y2 int) bool {
-	// Normalize coordinates
-	if x1 > x2 {
-		x1, x2 = x2, x1
-	}
-	if y1 > y2 {
-		y1, y2 = y2, y1
-	}
-
-	// Find first slab that could overlap (where YMax > y1)
-	startIdx := sort.Search(len(p.Slabs), func(i int) bool {
-		return p.Slabs[i].YMax > y1
-	})
-
-	// Check each relevant slab
-	for i := startIdx; i < len(p.Slabs); i++ {
-		slab := &p.Slabs[i]
-
-		// Stop if slab is entirely above our rectangle
-		if slab.YMin >= y2 {
-			break
-		}
-
-		// Check if rectangle's x-range [x1, x2] is fully contained in some inside interval
-		contained := false
-		for _, interval := range slab.InsideXRanges {
-			if interval.Left <= x1 && x2 <= interval.Right {
-				contained = true
-				break
-			}
-		}
-
-		if !contained {
-			return false
-		}
-	}
-
-	return true
+// LargestRectangleContext is LargestRectangle that stops early when ctx is
+// done, returning the best area found so far with ctx's error, and sends
+// report the vertices finished and the best area after each one
+func LargestRectangleContext(ctx context.Context, redTiles []Point, numWorkers int, report progress.Func) (int64, error) {
+	polygon := geometry.NewRectilinearPolygon(redTiles)
+	best, err := searchRectangles(ctx, redTiles, polygon, numWorkers, report)
+	return int64(best), err
 }
 
 // findLargestRectangleConcurrent uses goroutines to parallelize the search
 func findLargestRectangleConcurrent(redTiles []Point, polygon *Polygon, numWorkers int) int {
+	best, _ := searchRectangles(context.Background(), redTiles, polygon, numWorkers, nil)
+	return best
+}
+
+// searchRectangles hands each vertex to a pool of workers that pair it with
+// every later vertex
+func searchRectangles(ctx context.Context, redTiles []Point, polygon *Polygon, numWorkers int, report progress.Func) (int, error) {
 	n := len(redTiles)
 	if n < 2 {
-		return 0
+		return 0, nil
 	}
 
 	var globalMax atomic.Int64
@@ -218,8 +129,9 @@ func findLargestRectangleConcurrent(redTiles []Point, polygon *Polygon, numWorke
 	// Create work channel
 	workChan := make(chan int, n)
 
-	// Progress tracking
-	var processed atomic.Int64
+	// Progress tracking; the lock keeps reports in order
+	var mu sync.Mutex
+	processed := 0
 
 	// Spawn workers
 	for w := 0; w < numWorkers; w++ {
@@ -229,20 +141,18 @@ func findLargestRectangleConcurrent(redTiles []Point, polygon *Polygon, numWorke
 			localMax := int64(0)
 
 			for i := range workChan {
+				if ctx.Err() != nil {
+					continue
+				}
 				p1 := redTiles[i]
 
 				for j := i + 1; j < n; j++ {
 					p2 := redTiles[j]
 
-					// Skip if same row or column
-					if p1.X == p2.X || p1.Y == p2.Y {
-						continue
-					}
-
 					// Calculate area (including boundary tiles)
 					// Rectangle from (x1,y1) to (x2,y2) has width |x2-x1|+1 and height |y2-y1|+1
-					width := int64(abs(p2.X-p1.X) + 1)
-					height := int64(abs(p2.Y-p1.Y) + 1)
+					width := int64(geometry.Abs(p2.X-p1.X) + 1)
+					height := int64(geometry.Abs(p2.Y-p1.Y) + 1)
 					area := width * height
 
 					// Skip if can't beat current max
@@ -256,25 +166,23 @@ func findLargestRectangleConcurrent(redTiles []Point, polygon *Polygon, numWorke
 					}
 
 					// Check if rectangle is valid
-					if polygon.isRectangleValid(p1.X, p1.Y, p2.X, p2.Y) {
+					if polygon.ContainsTiles(p1, p2) {
 						localMax = area
 						// Update global max immediately for better pruning
 						for {
 							old := globalMax.Load()
-							if localMax <= old {
-								break
-							}
-							if globalMax.CompareAndSwap(old, localMax) {
-								fmt.Printf("New max found: %d\n", localMax)
+							if localMax <= old || globalMax.CompareAndSwap(old, localMax) {
 								break
 							}
 						}
 					}
 				}
 
-				count := processed.Add(1)
-				if count%50 == 0 {
-					fmt.Printf("Progress: %d/%d vertices (%.1f%%)\n", count, n, float64(count)*100/float64(n))
+				if report != nil {
+					mu.Lock()
+					processed++
+					report(progress.Report{Done: processed, Total: n, Best: globalMax.Load()})
+					mu.Unlock()
 				}
 			}
 		}(w)
@@ -287,12 +195,5 @@ func findLargestRectangleConcurrent(redTiles []Point, polygon *Polygon, numWorke
 	close(workChan)
 
 	wg.Wait()
-	return int(globalMax.Load())
-}
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
+	return int(globalMax.Load()), ctx.Err()
 }
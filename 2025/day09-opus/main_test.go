@@ -1,22 +1,30 @@
-package main
+package day09opus
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"aoc/pkg/geometry"
+	"aoc/pkg/polygen"
+	"aoc/pkg/progress"
 )
 
 // Test data from the problem description
 var exampleRedTiles = []Point{
-	{7, 1},
-	{11, 1},
-	{11, 7},
-	{9, 7},
-	{9, 5},
-	{2, 5},
-	{2, 3},
-	{7, 3},
+	{X: 7, Y: 1},
+	{X: 11, Y: 1},
+	{X: 11, Y: 7},
+	{X: 9, Y: 7},
+	{X: 9, Y: 5},
+	{X: 2, Y: 5},
+	{X: 2, Y: 3},
+	{X: 7, Y: 3},
 }
 
 // TestParseInput tests the input parsing function
@@ -76,116 +84,6 @@ func TestParseInputWithWhitespace(t *testing.T) {
 	}
 }
 
-// TestBuildPolygon tests polygon construction
-func TestBuildPolygon(t *testing.T) {
-	polygon := buildPolygon(exampleRedTiles)
-
-	if polygon == nil {
-		t.Fatal("Expected non-nil polygon")
-	}
-
-	if len(polygon.Vertices) != 8 {
-		t.Errorf("Expected 8 vertices, got %d", len(polygon.Vertices))
-	}
-
-	// Should have slabs between unique Y coordinates
-	// Y values: 1, 3, 5, 7 -> 3 slabs
-	if len(polygon.Slabs) != 3 {
-		t.Errorf("Expected 3 slabs, got %d", len(polygon.Slabs))
-	}
-
-	// Check Y coordinates are sorted
-	for i := 1; i < len(polygon.YCoords); i++ {
-		if polygon.YCoords[i] <= polygon.YCoords[i-1] {
-			t.Errorf("Y coordinates not sorted: %v", polygon.YCoords)
-			break
-		}
-	}
-}
-
-// TestBuildPolygonEmpty tests building polygon with no vertices
-func TestBuildPolygonEmpty(t *testing.T) {
-	polygon := buildPolygon([]Point{})
-
-	if polygon == nil {
-		t.Fatal("Expected non-nil polygon even for empty input")
-	}
-
-	if len(polygon.Vertices) != 0 {
-		t.Errorf("Expected 0 vertices, got %d", len(polygon.Vertices))
-	}
-}
-
-// TestIsRectangleValid tests rectangle validation
-func TestIsRectangleValid(t *testing.T) {
-	polygon := buildPolygon(exampleRedTiles)
-
-	tests := []struct {
-		name     string
-		x1, y1   int
-		x2, y2   int
-		expected bool
-	}{
-		{
-			name:     "Valid rectangle (2,3) to (9,5) - the 24 area answer",
-			x1:       2, y1: 3,
-			x2:       9, y2: 5,
-			expected: true,
-		},
-		{
-			name:     "Valid rectangle (9,5) to (11,7)",
-			x1:       9, y1: 5,
-			x2:       11, y2: 7,
-			expected: true,
-		},
-		{
-			name:     "Invalid rectangle outside polygon - far left",
-			x1:       0, y1: 0,
-			x2:       1, y2: 2,
-			expected: false,
-		},
-		{
-			name:     "Invalid rectangle crossing outside boundary",
-			x1:       1, y1: 1,
-			x2:       5, y2: 5,
-			expected: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := polygon.isRectangleValid(tt.x1, tt.y1, tt.x2, tt.y2)
-			if result != tt.expected {
-				t.Errorf("isRectangleValid(%d,%d,%d,%d) = %v, expected %v",
-					tt.x1, tt.y1, tt.x2, tt.y2, result, tt.expected)
-			}
-		})
-	}
-}
-
-// TestIsRectangleValidNormalization tests that coordinate order doesn't matter
-func TestIsRectangleValidNormalization(t *testing.T) {
-	polygon := buildPolygon(exampleRedTiles)
-
-	// These should all give the same result regardless of corner order
-	coords := []struct{ x1, y1, x2, y2 int }{
-		{2, 3, 9, 5},
-		{9, 3, 2, 5},
-		{2, 5, 9, 3},
-		{9, 5, 2, 3},
-	}
-
-	expected := polygon.isRectangleValid(2, 3, 9, 5)
-
-	for _, c := range coords {
-		result := polygon.isRectangleValid(c.x1, c.y1, c.x2, c.y2)
-		if result != expected {
-			t.Errorf("isRectangleValid(%d,%d,%d,%d) = %v, expected %v (normalization issue)",
-				c.x1, c.y1, c.x2, c.y2, result, expected)
-		}
-	}
-}
-
 // TestAreaCalculation tests that area includes boundary tiles
 func TestAreaCalculation(t *testing.T) {
 	// Rectangle from (2,3) to (9,5):
@@ -195,8 +93,8 @@ func TestAreaCalculation(t *testing.T) {
 	x1, y1 := 2, 3
 	x2, y2 := 9, 5
 	
-	width := abs(x2-x1) + 1
-	height := abs(y2-y1) + 1
+	width := geometry.Abs(x2-x1) + 1
+	height := geometry.Abs(y2-y1) + 1
 	area := width * height
 	
 	if area != 24 {
@@ -206,7 +104,7 @@ func TestAreaCalculation(t *testing.T) {
 
 // TestFindLargestRectangleConcurrent tests the main algorithm with example data
 func TestFindLargestRectangleConcurrent(t *testing.T) {
-	polygon := buildPolygon(exampleRedTiles)
+	polygon := geometry.NewRectilinearPolygon(exampleRedTiles)
 
 	// Test with different worker counts
 	workerCounts := []int{1, 2, 4, 8, 16}
@@ -229,13 +127,13 @@ func TestFindLargestRectangleConcurrent(t *testing.T) {
 func TestFindLargestRectangleSimpleSquare(t *testing.T) {
 	// Simple square polygon
 	points := []Point{
-		{0, 0},
-		{10, 0},
-		{10, 10},
-		{0, 10},
+		{X: 0, Y: 0},
+		{X: 10, Y: 0},
+		{X: 10, Y: 10},
+		{X: 0, Y: 10},
 	}
 
-	polygon := buildPolygon(points)
+	polygon := geometry.NewRectilinearPolygon(points)
 	result := findLargestRectangleConcurrent(points, polygon, 4)
 
 	// Largest rectangle between opposite corners: (0,0) to (10,10)
@@ -249,8 +147,8 @@ func TestFindLargestRectangleSimpleSquare(t *testing.T) {
 
 // TestFindLargestRectangleSinglePoint tests edge case
 func TestFindLargestRectangleSinglePoint(t *testing.T) {
-	points := []Point{{5, 5}}
-	polygon := buildPolygon(points)
+	points := []Point{{X: 5, Y: 5}}
+	polygon := geometry.NewRectilinearPolygon(points)
 	result := findLargestRectangleConcurrent(points, polygon, 4)
 
 	if result != 0 {
@@ -261,7 +159,7 @@ func TestFindLargestRectangleSinglePoint(t *testing.T) {
 // TestFindLargestRectangleEmpty tests edge case
 func TestFindLargestRectangleEmpty(t *testing.T) {
 	points := []Point{}
-	polygon := buildPolygon(points)
+	polygon := geometry.NewRectilinearPolygon(points)
 	result := findLargestRectangleConcurrent(points, polygon, 4)
 
 	if result != 0 {
@@ -271,73 +169,24 @@ func TestFindLargestRectangleEmpty(t *testing.T) {
 
 // TestFindLargestRectangleCollinearPoints tests points on same line
 func TestFindLargestRectangleCollinearPoints(t *testing.T) {
-	// All points on same X - no valid rectangle possible
+	// All points on same X: the only rectangle is the line itself, one
+	// tile wide, which lies on the boundary
 	points := []Point{
-		{5, 0},
-		{5, 5},
-		{5, 10},
+		{X: 5, Y: 0},
+		{X: 5, Y: 5},
+		{X: 5, Y: 10},
 	}
-	polygon := buildPolygon(points)
+	polygon := geometry.NewRectilinearPolygon(points)
 	result := findLargestRectangleConcurrent(points, polygon, 4)
 
-	if result != 0 {
-		t.Errorf("Expected 0 for collinear points, got %d", result)
-	}
-}
-
-// TestAbs tests the absolute value function
-func TestAbs(t *testing.T) {
-	tests := []struct {
-		input    int
-		expected int
-	}{
-		{5, 5},
-		{-5, 5},
-		{0, 0},
-		{-1000000, 1000000},
-		{1000000, 1000000},
-	}
-
-	for _, tt := range tests {
-		result := abs(tt.input)
-		if result != tt.expected {
-			t.Errorf("abs(%d) = %d, expected %d", tt.input, result, tt.expected)
-		}
-	}
-}
-
-// TestSlabStructure tests the internal slab structure
-func TestSlabStructure(t *testing.T) {
-	polygon := buildPolygon(exampleRedTiles)
-
-	if len(polygon.Slabs) == 0 {
-		t.Fatal("No slabs created")
-	}
-
-	// First slab should start at minimum Y
-	minY := polygon.YCoords[0]
-	if polygon.Slabs[0].YMin != minY {
-		t.Errorf("First slab YMin = %d, expected %d", polygon.Slabs[0].YMin, minY)
-	}
-
-	// Last slab should end at maximum Y
-	maxY := polygon.YCoords[len(polygon.YCoords)-1]
-	lastSlab := polygon.Slabs[len(polygon.Slabs)-1]
-	if lastSlab.YMax != maxY {
-		t.Errorf("Last slab YMax = %d, expected %d", lastSlab.YMax, maxY)
-	}
-
-	// Slabs should be contiguous
-	for i := 1; i < len(polygon.Slabs); i++ {
-		if polygon.Slabs[i].YMin != polygon.Slabs[i-1].YMax {
-			t.Errorf("Gap between slabs %d and %d", i-1, i)
-		}
+	if result != 11 {
+		t.Errorf("Expected 11 for collinear points, got %d", result)
 	}
 }
 
 // TestConcurrencyCorrectness runs the algorithm multiple times to check for race conditions
 func TestConcurrencyCorrectness(t *testing.T) {
-	polygon := buildPolygon(exampleRedTiles)
+	polygon := geometry.NewRectilinearPolygon(exampleRedTiles)
 
 	var results []int
 	for i := 0; i < 10; i++ {
@@ -355,39 +204,56 @@ func TestConcurrencyCorrectness(t *testing.T) {
 
 // TestRealInput tests with the actual puzzle input
 func TestRealInput(t *testing.T) {
-	if _, err := os.Stat("/tmp/aoc-day9/input.txt"); os.IsNotExist(err) {
-		t.Skip("Skipping real input test - input.txt not found")
+	if got := Run("input.txt"); got != 1539809693 {
+		t.Errorf("Expected 1539809693 for real input, got %d", got)
 	}
+}
 
-	redTiles := parseInput("/tmp/aoc-day9/input.txt")
-	polygon := buildPolygon(redTiles)
-	result := findLargestRectangleConcurrent(redTiles, polygon, 16)
+func TestSolveTiming(t *testing.T) {
+	var out bytes.Buffer
+	got, err := Solve(context.Background(), Options{Input: "input.txt", Workers: 2, Timing: true, Out: &out})
+	if err != nil || got != 1539809693 {
+		t.Fatalf("got %d, %v", got, err)
+	}
+	for _, want := range []string{"Parsed 496 red tiles", "Search time (2 workers)", "Total time"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("timings %q do not mention %q", out.String(), want)
+		}
+	}
 
-	// We'll update this once we know the correct answer
-	t.Logf("Real input result: %d", result)
-	if result <= 0 {
-		t.Errorf("Expected positive result for real input, got %d", result)
+	out.Reset()
+	if _, err := Solve(context.Background(), Options{Input: "input.txt", Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("printed %q without Timing", out.String())
+	}
+}
+
+func TestSolveBadInput(t *testing.T) {
+	if _, err := Solve(context.Background(), Options{Input: "missing.txt"}); err == nil {
+		t.Error("expected an error for a missing input")
 	}
 }
 
 // Benchmark tests
 func BenchmarkBuildPolygon(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		buildPolygon(exampleRedTiles)
+		geometry.NewRectilinearPolygon(exampleRedTiles)
 	}
 }
 
 func BenchmarkIsRectangleValid(b *testing.B) {
-	polygon := buildPolygon(exampleRedTiles)
+	polygon := geometry.NewRectilinearPolygon(exampleRedTiles)
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		polygon.isRectangleValid(2, 3, 9, 5)
+		polygon.ContainsTiles(Point{X: 2, Y: 3}, Point{X: 9, Y: 5})
 	}
 }
 
 func BenchmarkFindLargestRectangle1Worker(b *testing.B) {
-	polygon := buildPolygon(exampleRedTiles)
+	polygon := geometry.NewRectilinearPolygon(exampleRedTiles)
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
@@ -396,10 +262,88 @@ func BenchmarkFindLargestRectangle1Worker(b *testing.B) {
 }
 
 func BenchmarkFindLargestRectangle16Workers(b *testing.B) {
-	polygon := buildPolygon(exampleRedTiles)
+	polygon := geometry.NewRectilinearPolygon(exampleRedTiles)
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
 		findLargestRectangleConcurrent(exampleRedTiles, polygon, 16)
 	}
 }
+
+// generatedPolygons are puzzle sized polygons of each generated shape
+func generatedPolygons(b *testing.B) map[string][]Point {
+	polygons := map[string][]Point{}
+	for _, shape := range []polygen.Shape{polygen.Notched, polygen.Spiral, polygen.Corridor} {
+		redTiles, err := polygen.Generate(polygen.Options{Seed: 1, Shape: shape, Vertices: 496, MaxCoord: 100000})
+		if err != nil {
+			b.Fatal(err)
+		}
+		polygons[shape.String()] = redTiles
+	}
+	return polygons
+}
+
+func BenchmarkBuildPolygonGenerated(b *testing.B) {
+	for name, redTiles := range generatedPolygons(b) {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				geometry.NewRectilinearPolygon(redTiles)
+			}
+		})
+	}
+}
+
+func BenchmarkIsRectangleValidGenerated(b *testing.B) {
+	for name, redTiles := range generatedPolygons(b) {
+		polygon := geometry.NewRectilinearPolygon(redTiles)
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				polygon.ContainsTiles(redTiles[i%len(redTiles)], redTiles[(i*7)%len(redTiles)])
+			}
+		})
+	}
+}
+
+func BenchmarkFindLargestRectangleGenerated(b *testing.B) {
+	for name, redTiles := range generatedPolygons(b) {
+		polygon := geometry.NewRectilinearPolygon(redTiles)
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				findLargestRectangleConcurrent(redTiles, polygon, 1)
+			}
+		})
+	}
+}
+
+func TestLargestRectangleContextReportsProgress(t *testing.T) {
+	var reports []progress.Report
+	got, err := LargestRectangleContext(context.Background(), exampleRedTiles, 4, func(r progress.Report) {
+		reports = append(reports, r)
+	})
+	if err != nil || got != 24 {
+		t.Fatalf("got %d, %v; expected 24", got, err)
+	}
+	if len(reports) != len(exampleRedTiles) {
+		t.Fatalf("got %d reports, expected one per vertex", len(reports))
+	}
+	for i, r := range reports {
+		if r.Done != i+1 || r.Total != len(exampleRedTiles) {
+			t.Errorf("report %d: %+v", i, r)
+		}
+	}
+	if last := reports[len(reports)-1]; last.Best != 24 {
+		t.Errorf("last report best %d, expected 24", last.Best)
+	}
+}
+
+func TestLargestRectangleContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, err := LargestRectangleContext(ctx, exampleRedTiles, 4, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if got != 0 {
+		t.Errorf("expected no work done, got %d", got)
+	}
+}
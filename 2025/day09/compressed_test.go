@@ -0,0 +1,77 @@
+package day09
+
+import (
+	"math/rand"
+	"testing"
+
+	"aoc/pkg/geometry"
+	"aoc/pkg/polygen"
+)
+
+// tileContains checks every tile of the rectangle one by one
+func tileContains(polygon *geometry.RectilinearPolygon, p1, p2 Point) bool {
+	for x := min(p1.X, p2.X); x <= max(p1.X, p2.X); x++ {
+		for y := min(p1.Y, p2.Y); y <= max(p1.Y, p2.Y); y++ {
+			if !polygon.ContainsPoint(Point{X: x, Y: y}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TestCompressedGridMatchesSlabs compares every vertex pair on random
+// polygons, half of them with edges a tile apart, against the slab method
+// (as used by 2025/day09-opus) and a tile by tile check
+func TestCompressedGridMatchesSlabs(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	for round := 0; round < 200; round++ {
+		redTiles, err := polygen.Generate(polygen.Options{
+			Seed:        int64(round),
+			Shape:       polygen.Shape(round % 3),
+			Vertices:    4 + rng.Intn(20),
+			MaxCoord:    100,
+			OneTileGaps: round%2 == 1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		grid := geometry.NewCompressedGrid(redTiles)
+		polygon := geometry.NewRectilinearPolygon(redTiles)
+
+		var want int64
+		for i, p1 := range redTiles {
+			for _, p2 := range redTiles[i+1:] {
+				got := grid.ContainsTiles(p1, p2)
+				if tiles := tileContains(polygon, p1, p2); got != tiles {
+					t.Fatalf("polygon %v: ContainsTiles(%v, %v) = %v, tile check %v", redTiles, p1, p2, got, tiles)
+				}
+				slabs := polygon.ContainsTiles(p1, p2)
+				if got != slabs {
+					t.Fatalf("polygon %v: ContainsTiles(%v, %v) = %v, slabs %v", redTiles, p1, p2, got, slabs)
+				}
+				area := int64(geometry.Abs(p2.X-p1.X)+1) * int64(geometry.Abs(p2.Y-p1.Y)+1)
+				if slabs && area > want {
+					want = area
+				}
+			}
+		}
+		if got := findLargestRectangleOptimized(redTiles); got != want {
+			t.Fatalf("polygon %v: findLargestRectangleOptimized = %d, slab answer %d", redTiles, got, want)
+		}
+	}
+}
+
+func BenchmarkFindLargestRectangleOptimized(b *testing.B) {
+	for _, shape := range []polygen.Shape{polygen.Notched, polygen.Spiral, polygen.Corridor} {
+		redTiles, err := polygen.Generate(polygen.Options{Seed: 1, Shape: shape, Vertices: 496, MaxCoord: 100000})
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(shape.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				findLargestRectangleOptimized(redTiles)
+			}
+		})
+	}
+}
@@ -1,149 +1,129 @@
-package main
+package day09
 
 import (
-	"bufio"
-	"fmt"
-	"os"
+	"context"
 	"runtime"
-	"strconv"
-	"strings"
 	"sync"
+
+	"aoc/pkg/geometry"
+	"aoc/pkg/progress"
 )
 
-type Point struct {
-	x, y int
-}
+// Point comes from the shared geometry package
+type Point = geometry.Point
 
-// Grid-based bitmap for O(1) point lookups
-type ValidityGrid struct {
-	data map[int]map[int]bool
-	mu   sync.RWMutex
+// Run solves Part 2 for the puzzle input in inputFile
+func Run(inputFile string) int64 {
+	return RunPart(inputFile, 2)
 }
 
-func NewValidityGrid() *ValidityGrid {
-	return &ValidityGrid{
-		data: make(map[int]map[int]bool),
+// RunPart solves the given part for the puzzle input in inputFile. Part 1
+// takes any list of red tiles; part 2 needs them to form a valid polygon.
+func RunPart(inputFile string, part int) int64 {
+	if part == 1 {
+		return int64(findLargestRectangle(parseInput(inputFile)))
+	}
+	points, _, err := geometry.ReadPolygon(inputFile)
+	if err != nil {
+		panic(err)
 	}
+	return findLargestRectangleStaircase(points)
 }
 
-func (g *ValidityGrid) Set(x, y int) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-
-	if g.data[x] == nil {
-		g.data[x] = make(map[int]bool)
+// Part 1: the largest rectangle with red tiles at two opposite corners,
+// whatever else it covers
+func findLargestRectangle(points []Point) int {
+	largest := 0
+	for i, p1 := range points {
+		for _, p2 := range points[i+1:] {
+			width := geometry.Abs(p2.X-p1.X) + 1
+			height := geometry.Abs(p2.Y-p1.Y) + 1
+			largest = max(largest, width*height)
+		}
 	}
-	g.data[x][y] = true
+	return largest
 }
 
-func (g *ValidityGrid) Get(x, y int) bool {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
+// LargestRectangle is the largest rectangle with red corners that only
+// covers red or green tiles, found by the staircase search
+func LargestRectangle(redTiles []Point) int64 {
+	return findLargestRectangleStaircase(redTiles)
+}
 
-	if row, exists := g.data[x]; exists {
-		return row[y]
-	}
-	return false
+// LargestRectangleGrid is LargestRectangle by checking every vertex pair
+// against the compressed grid. It takes time and memory quadratic in the
+// number of vertices and is kept to check the staircase search against.
+func LargestRectangleGrid(redTiles []Point) int64 {
+	return findLargestRectangleOptimized(redTiles)
 }
 
-func main() {
-	fmt.Println("Day 09: Movie Theater - Finding Largest Rectangle (Part 2)")
-	result := run("input.txt")
-	fmt.Printf("Answer: %d\n", result)
+// BestRectangle is the rectangle behind LargestRectangle; ok is false when
+// there is none
+func BestRectangle(redTiles []Point) (rect geometry.Rect, ok bool) {
+	rect, ok, _ = bestStaircase(context.Background(), redTiles, nil)
+	return rect, ok
 }
 
-func run(inputFile string) int64 {
-	points := parseInput(inputFile)
-	return findLargestRectangleOptimized(points)
+// LargestRectangleContext is the staircase search as run by Run. It stops
+// early when ctx is done, returning the best area so far with ctx's error,
+// and reports the corners tried out of those that could still win.
+func LargestRectangleContext(ctx context.Context, redTiles []Point, report progress.Func) (int64, error) {
+	return searchStaircase(ctx, redTiles, report)
+}
+
+// LargestRectangleGridContext is LargestRectangleContext for the compressed
+// grid search, reporting the vertices finished
+func LargestRectangleGridContext(ctx context.Context, redTiles []Point, report progress.Func) (int64, error) {
+	return searchCompressed(ctx, redTiles, report)
 }
 
 func parseInput(filename string) []Point {
-	file, err := os.Open(filename)
+	points, err := geometry.ReadPoints(filename)
 	if err != nil {
 		panic(err)
 	}
-	defer file.Close()
-
-	var points []Point
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-		parts := strings.Split(line, ",")
-		x, _ := strconv.Atoi(parts[0])
-		y, _ := strconv.Atoi(parts[1])
-		points = append(points, Point{x, y})
-	}
 	return points
 }
 
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
+// Optimized version using bitmap preprocessing and parallelization
+func findLargestRectangleOptimized(redTiles []Point) int64 {
+	best, _ := searchCompressed(context.Background(), redTiles, nil)
+	return best
 }
 
-// Build grid with only boundary tiles (no expensive flood fill)
-func buildBoundaryGrid(redTiles []Point) *ValidityGrid {
-	grid := NewValidityGrid()
-
-	// Add all red tiles
-	for _, p := range redTiles {
-		grid.Set(p.x, p.y)
+// searchCompressed is bestCompressed reduced to the area
+func searchCompressed(ctx context.Context, redTiles []Point, report progress.Func) (int64, error) {
+	rect, ok, err := bestCompressed(ctx, redTiles, report)
+	if !ok {
+		return 0, err
 	}
-
-	// Connect red tiles with green tiles
-	for i := 0; i < len(redTiles); i++ {
-		from := redTiles[i]
-		to := redTiles[(i+1)%len(redTiles)]
-
-		// Add all points on the line between from and to
-		if from.x == to.x {
-			// Vertical line
-			minY := from.y
-			maxY := to.y
-			if minY > maxY {
-				minY, maxY = maxY, minY
-			}
-			for y := minY; y <= maxY; y++ {
-				grid.Set(from.x, y)
-			}
-		} else if from.y == to.y {
-			// Horizontal line
-			minX := from.x
-			maxX := to.x
-			if minX > maxX {
-				minX, maxX = maxX, minX
-			}
-			for x := minX; x <= maxX; x++ {
-				grid.Set(x, from.y)
-			}
-		}
-	}
-
-	return grid
+	return rect.Area(), err
 }
 
-// Optimized version using bitmap preprocessing and parallelization
-func findLargestRectangleOptimized(redTiles []Point) int64 {
+// bestCompressed checks every vertex pair against the compressed grid,
+// stopping early when ctx is done and reporting each finished vertex, and
+// returns the largest rectangle that fits
+func bestCompressed(ctx context.Context, redTiles []Point, report progress.Func) (geometry.Rect, bool, error) {
 	if len(redTiles) < 2 {
-		return 0
+		return geometry.Rect{}, false, nil
 	}
 
-	// Phase 1: Build the boundary grid (no flood fill to save time)
-	boundaryGrid := buildBoundaryGrid(redTiles)
+	// Phase 1: Classify the compressed cells once for exact rectangle checks
+	compressed := geometry.NewCompressedGrid(redTiles)
 
 	// Phase 2: Parallel enumeration and validation
 	numWorkers := runtime.NumCPU()
-	var maxArea int64
 
 	// Divide work: each worker handles pairs where first point index is in its range
 	chunkSize := (len(redTiles) + numWorkers - 1) / numWorkers
 	var wg sync.WaitGroup
-	results := make([]int64, numWorkers)
+	results := make([]geometry.Rect, numWorkers)
+	found := make([]bool, numWorkers)
+
+	// Progress is shared between the workers
+	var mu sync.Mutex
+	done := 0
+	var best int64
 
 	for w := 0; w < numWorkers; w++ {
 		wg.Add(1)
@@ -157,136 +137,52 @@ func findLargestRectangleOptimized(redTiles []Point) int64 {
 			}
 
 			localMax := int64(0)
+			var localRect geometry.Rect
 
 			// Process all pairs where first index is in this worker's range
-			for i := startIdx; i < endIdx; i++ {
+			for i := startIdx; i < endIdx && ctx.Err() == nil; i++ {
 				p1 := redTiles[i]
 
 				// Try all points after this one
 				for j := i + 1; j < len(redTiles); j++ {
 					p2 := redTiles[j]
 
-					width := int64(abs(p2.x-p1.x)) + 1
-					height := int64(abs(p2.y-p1.y)) + 1
+					width := int64(geometry.Abs(p2.X-p1.X)) + 1
+					height := int64(geometry.Abs(p2.Y-p1.Y)) + 1
 					area := width * height
 
 					// Only validate if potentially better
 					if area > localMax {
-						if isValidRectangle(p1, p2, boundaryGrid, redTiles) {
+						if compressed.ContainsTiles(p1, p2) {
 							localMax = area
+							localRect = geometry.Rect{A: p1, B: p2}
 						}
 					}
 				}
+
+				if report != nil {
+					mu.Lock()
+					done++
+					best = max(best, localMax)
+					report(progress.Report{Done: done, Total: len(redTiles), Best: best})
+					mu.Unlock()
+				}
 			}
 
-			results[workerID] = localMax
+			results[workerID], found[workerID] = localRect, localMax > 0
 		}(w)
 	}
 
 	wg.Wait()
 
 	// Find global maximum
-	for _, result := range results {
-		if result > maxArea {
-			maxArea = result
+	var winner geometry.Rect
+	ok := false
+	for w, result := range results {
+		if found[w] && (!ok || result.Area() > winner.Area()) {
+			winner, ok = result, true
 		}
 	}
 
-	return maxArea
-}
-
-// Ray casting for point-in-polygon
-func isInsidePolygon(p Point, polygon []Point) bool {
-	if len(polygon) < 3 {
-		return false
-	}
-
-	count := 0
-	for i := 0; i < len(polygon); i++ {
-		p1 := polygon[i]
-		p2 := polygon[(i+1)%len(polygon)]
-
-		if (p1.y <= p.y && p.y < p2.y) || (p2.y <= p.y && p.y < p1.y) {
-			xinters := float64(p1.x) + float64(p.y-p1.y)*float64(p2.x-p1.x)/float64(p2.y-p1.y)
-			if float64(p.x) < xinters {
-				count++
-			}
-		}
-	}
-
-	return count%2 == 1
-}
-
-// Check if rectangle is valid: all points must be on boundary OR inside polygon
-func isValidRectangle(p1, p2 Point, boundaryGrid *ValidityGrid, redTiles []Point) bool {
-	minX := p1.x
-	maxX := p2.x
-	if minX > maxX {
-		minX, maxX = maxX, minX
-	}
-
-	minY := p1.y
-	maxY := p2.y
-	if minY > maxY {
-		minY, maxY = maxY, minY
-	}
-
-	// Check all corners first (quick rejection)
-	if !boundaryGrid.Get(minX, minY) && !isInsidePolygon(Point{minX, minY}, redTiles) {
-		return false
-	}
-	if !boundaryGrid.Get(maxX, maxY) && !isInsidePolygon(Point{maxX, maxY}, redTiles) {
-		return false
-	}
-	if !boundaryGrid.Get(minX, maxY) && !isInsidePolygon(Point{minX, maxY}, redTiles) {
-		return false
-	}
-	if !boundaryGrid.Get(maxX, minY) && !isInsidePolygon(Point{maxX, minY}, redTiles) {
-		return false
-	}
-
-	// Sample interior points to validate
-	// Don't check every point - use adaptive sampling
-	sampleRate := 1
-	if (maxX - minX) > 1000 || (maxY - minY) > 1000 {
-		sampleRate = 100
-	}
-
-	for x := minX; x <= maxX; x += sampleRate {
-		for y := minY; y <= maxY; y += sampleRate {
-			if !boundaryGrid.Get(x, y) && !isInsidePolygon(Point{x, y}, redTiles) {
-				return false
-			}
-		}
-	}
-
-	// If sample passed, check edges more densely
-	for x := minX; x <= maxX; x += max(1, sampleRate/10) {
-		// Top and bottom edges
-		if !boundaryGrid.Get(x, minY) && !isInsidePolygon(Point{x, minY}, redTiles) {
-			return false
-		}
-		if !boundaryGrid.Get(x, maxY) && !isInsidePolygon(Point{x, maxY}, redTiles) {
-			return false
-		}
-	}
-
-	for y := minY; y <= maxY; y += max(1, sampleRate/10) {
-		// Left and right edges
-		if !boundaryGrid.Get(minX, y) && !isInsidePolygon(Point{minX, y}, redTiles) {
-			return false
-		}
-		if !boundaryGrid.Get(maxX, y) && !isInsidePolygon(Point{maxX, y}, redTiles) {
-			return false
-		}
-	}
-
-	return true
-}
-
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
+	return winner, ok, ctx.Err()
 }
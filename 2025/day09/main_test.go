@@ -1,7 +1,14 @@
-package main
+package day09
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"testing"
+
+	"aoc/pkg/geometry"
+	"aoc/pkg/polygen"
+	"aoc/pkg/progress"
 )
 
 func TestFindLargestRectangle(t *testing.T) {
@@ -13,8 +20,8 @@ func TestFindLargestRectangle(t *testing.T) {
 		{
 			name: "Example from problem - area 50 (corners 2,5 and 11,1)",
 			points: []Point{
-				{7, 1}, {11, 1}, {11, 7}, {9, 7}, {9, 5},
-				{2, 5}, {2, 3}, {7, 3},
+				{X: 7, Y: 1}, {X: 11, Y: 1}, {X: 11, Y: 7}, {X: 9, Y: 7}, {X: 9, Y: 5},
+				{X: 2, Y: 5}, {X: 2, Y: 3}, {X: 7, Y: 3},
 			},
 			expected: 50,
 		},
@@ -26,91 +33,91 @@ func TestFindLargestRectangle(t *testing.T) {
 		{
 			name: "Single point",
 			points: []Point{
-				{5, 5},
+				{X: 5, Y: 5},
 			},
 			expected: 0,
 		},
 		{
 			name: "Two points - vertical line (same x, area 11)",
 			points: []Point{
-				{5, 0}, {5, 10},
+				{X: 5, Y: 0}, {X: 5, Y: 10},
 			},
 			expected: 11,
 		},
 		{
 			name: "Two points - horizontal line (same y, area 11)",
 			points: []Point{
-				{0, 5}, {10, 5},
+				{X: 0, Y: 5}, {X: 10, Y: 5},
 			},
 			expected: 11,
 		},
 		{
 			name: "Two points - thin rectangle (area 6, 1 tall line)",
 			points: []Point{
-				{2, 3}, {7, 3},
+				{X: 2, Y: 3}, {X: 7, Y: 3},
 			},
 			expected: 6,
 		},
 		{
 			name: "Two points - rectangle (area 24)",
 			points: []Point{
-				{2, 5}, {9, 7},
+				{X: 2, Y: 5}, {X: 9, Y: 7},
 			},
 			expected: 24,
 		},
 		{
 			name: "Three points - max area 121",
 			points: []Point{
-				{0, 0}, {5, 5}, {10, 10},
+				{X: 0, Y: 0}, {X: 5, Y: 5}, {X: 10, Y: 10},
 			},
 			expected: 121,
 		},
 		{
 			name: "Four points - square (area 36)",
 			points: []Point{
-				{0, 0}, {5, 0}, {0, 5}, {5, 5},
+				{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 0, Y: 5}, {X: 5, Y: 5},
 			},
 			expected: 36,
 		},
 		{
 			name: "Negative coordinates (area 121)",
 			points: []Point{
-				{-5, -5}, {5, 5},
+				{X: -5, Y: -5}, {X: 5, Y: 5},
 			},
 			expected: 121,
 		},
 		{
 			name: "Mixed positive and negative (area 231)",
 			points: []Point{
-				{-10, -5}, {10, 5},
+				{X: -10, Y: -5}, {X: 10, Y: 5},
 			},
 			expected: 231,
 		},
 		{
 			name: "All points on same x-axis (max area 16, from 0 to 15)",
 			points: []Point{
-				{0, 0}, {5, 0}, {10, 0}, {15, 0},
+				{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 10, Y: 0}, {X: 15, Y: 0},
 			},
 			expected: 16,
 		},
 		{
 			name: "All points on same y-axis (max area 16, from 0 to 15)",
 			points: []Point{
-				{0, 0}, {0, 5}, {0, 10}, {0, 15},
+				{X: 0, Y: 0}, {X: 0, Y: 5}, {X: 0, Y: 10}, {X: 0, Y: 15},
 			},
 			expected: 16,
 		},
 		{
 			name: "Large coordinate values (same y, area 675)",
 			points: []Point{
-				{97615, 50359}, {98289, 50359},
+				{X: 97615, Y: 50359}, {X: 98289, Y: 50359},
 			},
 			expected: 675,
 		},
 		{
 			name: "Two points with large area (1002001)",
 			points: []Point{
-				{0, 0}, {1000, 1000},
+				{X: 0, Y: 0}, {X: 1000, Y: 1000},
 			},
 			expected: 1002001,
 		},
@@ -140,9 +147,9 @@ func TestAbsFunction(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("", func(t *testing.T) {
-			result := abs(tt.input)
+			result := geometry.Abs(tt.input)
 			if result != tt.expected {
-				t.Errorf("abs(%d) = %d, expected %d", tt.input, result, tt.expected)
+				t.Errorf("Abs(%d) = %d, expected %d", tt.input, result, tt.expected)
 			}
 		})
 	}
@@ -155,7 +162,7 @@ func TestParseInput(t *testing.T) {
 			t.Error("Expected points to be parsed from input file")
 		}
 		// Verify first point is parsed correctly
-		if points[0].x == 0 && points[0].y == 0 {
+		if points[0].X == 0 && points[0].Y == 0 {
 			t.Error("Expected valid coordinates, got 0,0")
 		}
 	})
@@ -170,52 +177,52 @@ func TestRectangleAreaCalculation(t *testing.T) {
 	}{
 		{
 			name:    "Simple rectangle 3x4 (inclusive)",
-			p1:      Point{0, 0},
-			p2:      Point{2, 3},
+			p1:      Point{X: 0, Y: 0},
+			p2:      Point{X: 2, Y: 3},
 			expArea: 12,
 		},
 		{
 			name:    "Rectangle 6x11 (inclusive)",
-			p1:      Point{0, 0},
-			p2:      Point{5, 10},
+			p1:      Point{X: 0, Y: 0},
+			p2:      Point{X: 5, Y: 10},
 			expArea: 66,
 		},
 		{
 			name:    "Square 6x6 (inclusive)",
-			p1:      Point{0, 0},
-			p2:      Point{5, 5},
+			p1:      Point{X: 0, Y: 0},
+			p2:      Point{X: 5, Y: 5},
 			expArea: 36,
 		},
 		{
 			name:    "Reversed coordinates (same result)",
-			p1:      Point{10, 10},
-			p2:      Point{0, 0},
+			p1:      Point{X: 10, Y: 10},
+			p2:      Point{X: 0, Y: 0},
 			expArea: 121,
 		},
 		{
 			name:    "Same point (area 1)",
-			p1:      Point{5, 5},
-			p2:      Point{5, 5},
+			p1:      Point{X: 5, Y: 5},
+			p2:      Point{X: 5, Y: 5},
 			expArea: 1,
 		},
 		{
 			name:    "Same x - vertical line (width 1)",
-			p1:      Point{5, 0},
-			p2:      Point{5, 10},
+			p1:      Point{X: 5, Y: 0},
+			p2:      Point{X: 5, Y: 10},
 			expArea: 11,
 		},
 		{
 			name:    "Same y - horizontal line (height 1)",
-			p1:      Point{0, 5},
-			p2:      Point{10, 5},
+			p1:      Point{X: 0, Y: 5},
+			p2:      Point{X: 10, Y: 5},
 			expArea: 11,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			width := abs(tt.p2.x - tt.p1.x) + 1
-			height := abs(tt.p2.y - tt.p1.y) + 1
+			width := geometry.Abs(tt.p2.X-tt.p1.X) + 1
+			height := geometry.Abs(tt.p2.Y-tt.p1.Y) + 1
 			area := width * height
 
 			if area != tt.expArea {
@@ -228,7 +235,7 @@ func TestRectangleAreaCalculation(t *testing.T) {
 func TestEdgeCases(t *testing.T) {
 	t.Run("Very close coordinates", func(t *testing.T) {
 		points := []Point{
-			{0, 0}, {1, 1},
+			{X: 0, Y: 0}, {X: 1, Y: 1},
 		}
 		result := findLargestRectangle(points)
 		if result != 4 {
@@ -238,7 +245,7 @@ func TestEdgeCases(t *testing.T) {
 
 	t.Run("Duplicate points", func(t *testing.T) {
 		points := []Point{
-			{5, 5}, {5, 5}, {10, 10},
+			{X: 5, Y: 5}, {X: 5, Y: 5}, {X: 10, Y: 10},
 		}
 		result := findLargestRectangle(points)
 		expected := 36
@@ -249,7 +256,7 @@ func TestEdgeCases(t *testing.T) {
 
 	t.Run("Three collinear points", func(t *testing.T) {
 		points := []Point{
-			{0, 0}, {5, 5}, {10, 10},
+			{X: 0, Y: 0}, {X: 5, Y: 5}, {X: 10, Y: 10},
 		}
 		result := findLargestRectangle(points)
 		if result != 121 {
@@ -259,7 +266,7 @@ func TestEdgeCases(t *testing.T) {
 
 	t.Run("Many points, largest is last pair", func(t *testing.T) {
 		points := []Point{
-			{1, 1}, {2, 2}, {3, 3}, {0, 0}, {100, 100},
+			{X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}, {X: 0, Y: 0}, {X: 100, Y: 100},
 		}
 		result := findLargestRectangle(points)
 		if result != 10201 {
@@ -272,7 +279,7 @@ func BenchmarkFindLargestRectangle(b *testing.B) {
 	// Create a set of points for benchmarking
 	var points []Point
 	for i := 0; i < 100; i++ {
-		points = append(points, Point{i, i})
+		points = append(points, Point{X: i, Y: i})
 	}
 
 	b.ResetTimer()
@@ -292,14 +299,14 @@ func TestIdentifyRedGreenTiles(t *testing.T) {
 		{
 			name: "Simple square boundary",
 			redTiles: []Point{
-				{0, 0}, {0, 2}, {2, 2}, {2, 0},
+				{X: 0, Y: 0}, {X: 0, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 0},
 			},
 			expectedCount: 8, // boundary tiles only
 		},
 		{
 			name: "Vertical line",
 			redTiles: []Point{
-				{0, 0}, {0, 2},
+				{X: 0, Y: 0}, {X: 0, Y: 2},
 			},
 			expectedCount: 3,
 		},
@@ -324,26 +331,26 @@ func TestAddLinePoints(t *testing.T) {
 	}{
 		{
 			name:     "Vertical line from (0,0) to (0,5)",
-			p1:       Point{0, 0},
-			p2:       Point{0, 5},
+			p1:       Point{X: 0, Y: 0},
+			p2:       Point{X: 0, Y: 5},
 			expected: 6,
 		},
 		{
 			name:     "Horizontal line from (0,0) to (5,0)",
-			p1:       Point{0, 0},
-			p2:       Point{5, 0},
+			p1:       Point{X: 0, Y: 0},
+			p2:       Point{X: 5, Y: 0},
 			expected: 6,
 		},
 		{
 			name:     "Single point (same coordinates)",
-			p1:       Point{3, 3},
-			p2:       Point{3, 3},
+			p1:       Point{X: 3, Y: 3},
+			p2:       Point{X: 3, Y: 3},
 			expected: 1,
 		},
 		{
 			name:     "Vertical line reversed",
-			p1:       Point{0, 5},
-			p2:       Point{0, 0},
+			p1:       Point{X: 0, Y: 5},
+			p2:       Point{X: 0, Y: 0},
 			expected: 6,
 		},
 	}
@@ -370,7 +377,7 @@ func TestGetBounds(t *testing.T) {
 	}{
 		{
 			name:      "Simple rectangle",
-			points:    []Point{{0, 0}, {5, 10}},
+			points:    []Point{{X: 0, Y: 0}, {X: 5, Y: 10}},
 			expMinX:   0,
 			expMaxX:   5,
 			expMinY:   0,
@@ -378,7 +385,7 @@ func TestGetBounds(t *testing.T) {
 		},
 		{
 			name:      "Negative coordinates",
-			points:    []Point{{-5, -10}, {5, 10}},
+			points:    []Point{{X: -5, Y: -10}, {X: 5, Y: 10}},
 			expMinX:   -5,
 			expMaxX:   5,
 			expMinY:   -10,
@@ -386,7 +393,7 @@ func TestGetBounds(t *testing.T) {
 		},
 		{
 			name:      "Single point",
-			points:    []Point{{3, 7}},
+			points:    []Point{{X: 3, Y: 7}},
 			expMinX:   3,
 			expMaxX:   3,
 			expMinY:   7,
@@ -415,33 +422,33 @@ func TestIsInsidePolygon(t *testing.T) {
 	}{
 		{
 			name:  "Point inside square",
-			point: Point{2, 2},
+			point: Point{X: 2, Y: 2},
 			polygon: []Point{
-				{0, 0}, {4, 0}, {4, 4}, {0, 4},
+				{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4},
 			},
 			expected: true,
 		},
 		{
 			name:  "Point outside square",
-			point: Point{5, 5},
+			point: Point{X: 5, Y: 5},
 			polygon: []Point{
-				{0, 0}, {4, 0}, {4, 4}, {0, 4},
+				{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4},
 			},
 			expected: false,
 		},
 		{
 			name:  "Point inside at (1, 1)",
-			point: Point{1, 1},
+			point: Point{X: 1, Y: 1},
 			polygon: []Point{
-				{0, 0}, {4, 0}, {4, 4}, {0, 4},
+				{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4},
 			},
 			expected: true,
 		},
 		{
 			name:  "Point outside at (-1, -1)",
-			point: Point{-1, -1},
+			point: Point{X: -1, Y: -1},
 			polygon: []Point{
-				{0, 0}, {4, 0}, {4, 4}, {0, 4},
+				{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4},
 			},
 			expected: false,
 		},
@@ -449,9 +456,9 @@ func TestIsInsidePolygon(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := isInsidePolygon(tt.point, tt.polygon)
+			result := geometry.NewRectilinearPolygon(tt.polygon).ContainsPoint(tt.point)
 			if result != tt.expected {
-				t.Errorf("isInsidePolygon: expected %v, got %v", tt.expected, result)
+				t.Errorf("ContainsPoint: expected %v, got %v", tt.expected, result)
 			}
 		})
 	}
@@ -463,7 +470,7 @@ func TestIsValidRectangle(t *testing.T) {
 	// Mark a 3x3 square as red/green
 	for x := 0; x < 3; x++ {
 		for y := 0; y < 3; y++ {
-			redGreen[Point{x, y}] = true
+			redGreen[Point{X: x, Y: y}] = true
 		}
 	}
 
@@ -475,26 +482,26 @@ func TestIsValidRectangle(t *testing.T) {
 	}{
 		{
 			name:     "Valid rectangle within red/green area",
-			p1:       Point{0, 0},
-			p2:       Point{1, 1},
+			p1:       Point{X: 0, Y: 0},
+			p2:       Point{X: 1, Y: 1},
 			expected: true,
 		},
 		{
 			name:     "Valid rectangle larger area",
-			p1:       Point{0, 0},
-			p2:       Point{2, 2},
+			p1:       Point{X: 0, Y: 0},
+			p2:       Point{X: 2, Y: 2},
 			expected: true,
 		},
 		{
 			name:     "Invalid rectangle outside area",
-			p1:       Point{0, 0},
-			p2:       Point{5, 5},
+			p1:       Point{X: 0, Y: 0},
+			p2:       Point{X: 5, Y: 5},
 			expected: false,
 		},
 		{
 			name:     "Valid single point",
-			p1:       Point{1, 1},
-			p2:       Point{1, 1},
+			p1:       Point{X: 1, Y: 1},
+			p2:       Point{X: 1, Y: 1},
 			expected: true,
 		},
 	}
@@ -518,28 +525,28 @@ func TestFindLargestRectanglePart2(t *testing.T) {
 		{
 			name: "Simple loop - area 9 (3x3 square)",
 			redTiles: []Point{
-				{0, 0}, {0, 2}, {2, 2}, {2, 0},
+				{X: 0, Y: 0}, {X: 0, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 0},
 			},
 			expected: 9,
 		},
 		{
 			name: "Simple horizontal line",
 			redTiles: []Point{
-				{0, 0}, {2, 0},
+				{X: 0, Y: 0}, {X: 2, Y: 0},
 			},
 			expected: 3,
 		},
 		{
 			name: "Single point",
 			redTiles: []Point{
-				{5, 5},
+				{X: 5, Y: 5},
 			},
 			expected: 0,
 		},
 		{
 			name: "Two points vertical",
 			redTiles: []Point{
-				{0, 0}, {0, 2},
+				{X: 0, Y: 0}, {X: 0, Y: 2},
 			},
 			expected: 3,
 		},
@@ -559,16 +566,16 @@ func BenchmarkFindLargestRectanglePart2(b *testing.B) {
 	// Create a rectangular loop
 	var points []Point
 	for x := 0; x < 20; x++ {
-		points = append(points, Point{x, 0})
+		points = append(points, Point{X: x, Y: 0})
 	}
 	for y := 1; y < 20; y++ {
-		points = append(points, Point{19, y})
+		points = append(points, Point{X: 19, Y: y})
 	}
 	for x := 18; x >= 0; x-- {
-		points = append(points, Point{x, 19})
+		points = append(points, Point{X: x, Y: 19})
 	}
 	for y := 18; y > 0; y-- {
-		points = append(points, Point{0, y})
+		points = append(points, Point{X: 0, Y: y})
 	}
 
 	b.ResetTimer()
@@ -576,3 +583,71 @@ func BenchmarkFindLargestRectanglePart2(b *testing.B) {
 		findLargestRectanglePart2(points)
 	}
 }
+
+func TestLargestRectangleContext(t *testing.T) {
+	redTiles, err := polygen.Generate(polygen.Options{Seed: 7, Vertices: 200, MaxCoord: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := findLargestRectangleOptimized(redTiles)
+	searches := []struct {
+		name   string
+		search func(context.Context, []Point, progress.Func) (int64, error)
+	}{
+		{"staircase", LargestRectangleContext},
+		{"grid", LargestRectangleGridContext},
+	}
+	for _, s := range searches {
+		var last progress.Report
+		got, err := s.search(context.Background(), redTiles, func(r progress.Report) {
+			if r.Done < last.Done || r.Best < last.Best {
+				t.Errorf("%s: report %+v went backwards from %+v", s.name, r, last)
+			}
+			last = r
+		})
+		if err != nil || got != want {
+			t.Fatalf("%s: got %d, %v; want %d", s.name, got, err, want)
+		}
+		if last.Done != last.Total || last.Best != got {
+			t.Errorf("%s: final report %+v", s.name, last)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := s.search(ctx, redTiles, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", s.name, err)
+		}
+	}
+}
+
+// oneTileSlit is a square with a slit one tile wide cut up from the bottom
+// edge. The slit holds no tiles, so the whole square is red or green.
+var oneTileSlit = []Point{
+	{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 6, Y: 10},
+	{X: 6, Y: 2}, {X: 5, Y: 2}, {X: 5, Y: 10}, {X: 0, Y: 10},
+}
+
+func TestRunOneTileSlit(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "input.txt")
+	if err := polygen.WriteFile(filename, oneTileSlit); err != nil {
+		t.Fatal(err)
+	}
+	if got := Run(filename); got != 121 {
+		t.Errorf("Run = %d, expected 121", got)
+	}
+	if got := LargestRectangle(oneTileSlit); got != 121 {
+		t.Errorf("LargestRectangle = %d, expected 121", got)
+	}
+	if got := LargestRectangleGrid(oneTileSlit); got != 121 {
+		t.Errorf("LargestRectangleGrid = %d, expected 121", got)
+	}
+	if rect, ok := BestRectangle(oneTileSlit); !ok || rect.Area() != 121 {
+		t.Errorf("BestRectangle = %v, %v; expected the whole square", rect, ok)
+	}
+}
+
+func TestRunRealInput(t *testing.T) {
+	if got := Run("input.txt"); got != 1539809693 {
+		t.Errorf("expected 1539809693, got %d", got)
+	}
+}
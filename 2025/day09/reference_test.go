@@ -0,0 +1,89 @@
+package day09
+
+import "aoc/pkg/geometry"
+
+// Reference implementations the tests check the solvers against. They
+// walk every tile, so they only suit small inputs.
+
+// Part 2 by brute force: flood the outside of the loop tile by tile, then
+// try every pair of red tiles against the tiles that are left
+func findLargestRectanglePart2(redTiles []Point) int {
+	redGreen := identifyRedGreenTiles(redTiles)
+	minX, maxX, minY, maxY := getBounds(redTiles)
+	minX, maxX, minY, maxY = minX-1, maxX+1, minY-1, maxY+1
+
+	start := Point{X: minX, Y: minY}
+	outside := map[Point]bool{start: true}
+	queue := []Point{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, n := range []Point{{X: p.X + 1, Y: p.Y}, {X: p.X - 1, Y: p.Y}, {X: p.X, Y: p.Y + 1}, {X: p.X, Y: p.Y - 1}} {
+			if n.X < minX || n.X > maxX || n.Y < minY || n.Y > maxY || outside[n] || redGreen[n] {
+				continue
+			}
+			outside[n] = true
+			queue = append(queue, n)
+		}
+	}
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			if p := (Point{X: x, Y: y}); !outside[p] {
+				redGreen[p] = true
+			}
+		}
+	}
+
+	largest := 0
+	for i, p1 := range redTiles {
+		for _, p2 := range redTiles[i+1:] {
+			area := (geometry.Abs(p2.X-p1.X) + 1) * (geometry.Abs(p2.Y-p1.Y) + 1)
+			if area > largest && isValidRectangle(p1, p2, redGreen) {
+				largest = area
+			}
+		}
+	}
+	return largest
+}
+
+// identifyRedGreenTiles marks the red tiles and the green tiles on the loop
+// between them, leaving out the green tiles inside the loop
+func identifyRedGreenTiles(redTiles []Point) map[Point]bool {
+	redGreen := make(map[Point]bool)
+	for i, p := range redTiles {
+		addLinePoints(p, redTiles[(i+1)%len(redTiles)], redGreen)
+	}
+	return redGreen
+}
+
+// addLinePoints marks every tile on the straight line from p1 to p2, both
+// ends included; a diagonal pair marks nothing
+func addLinePoints(p1, p2 Point, redGreen map[Point]bool) {
+	switch {
+	case p1.X == p2.X:
+		for y := min(p1.Y, p2.Y); y <= max(p1.Y, p2.Y); y++ {
+			redGreen[Point{X: p1.X, Y: y}] = true
+		}
+	case p1.Y == p2.Y:
+		for x := min(p1.X, p2.X); x <= max(p1.X, p2.X); x++ {
+			redGreen[Point{X: x, Y: p1.Y}] = true
+		}
+	}
+}
+
+func getBounds(points []Point) (minX, maxX, minY, maxY int) {
+	return geometry.Bounds(points)
+}
+
+// isValidRectangle checks tile by tile that the rectangle between p1 and
+// p2 only covers marked tiles
+func isValidRectangle(p1, p2 Point, redGreen map[Point]bool) bool {
+	for x := min(p1.X, p2.X); x <= max(p1.X, p2.X); x++ {
+		for y := min(p1.Y, p2.Y); y <= max(p1.Y, p2.Y); y++ {
+			if !redGreen[Point{X: x, Y: y}] {
+				return false
+			}
+		}
+	}
+	return true
+}
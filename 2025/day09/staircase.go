@@ -0,0 +1,310 @@
+package day09
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"aoc/pkg/geometry"
+	"aoc/pkg/interval"
+	"aoc/pkg/progress"
+)
+
+// The staircase search avoids checking every vertex pair. Looking from a
+// red corner p into one quadrant, walk the rows away from p and keep W, the
+// furthest x the rectangle can reach from p.x: on each row it is cut down
+// to the end of the run of tiles holding p.x. The far corner q of a valid
+// rectangle must sit under that staircase, and on each vertex row the best
+// q is the red tile furthest out within W. It works tile by tile, so a
+// rectangle may cross a gap between two edges a tile apart.
+//
+// Rows only change the run holding p.x where a horizontal edge shares an x
+// with it, so the walk jumps from one such row to the next with a segment
+// tree over the edges. Every (corner, quadrant) gets an upper bound from
+// the runs of tiles through p along its row and its column, and corners
+// are tried best bound first. A walk stops as soon as the room left cannot
+// beat the best area so far, so on puzzle shaped inputs only a handful of
+// corners get far.
+//
+// The row tiles come from the slab decomposition, which holds the runs of
+// every slab: few on puzzle shaped inputs, but on a spiral every slab
+// crosses most of the edges, and time and memory grow quadratically.
+
+// staircase is the polygon as the search reads it
+type staircase struct {
+	rows  *geometry.RectilinearPolygon // tiles of every row
+	cols  *geometry.RectilinearPolygon // transposed: tiles of every column
+	rowXs [][]int                      // x of the red tiles on each vertex row, sorted
+	edges *edgeIndex
+}
+
+// candidate is one corner and quadrant with its area bound
+type candidate struct {
+	index  int
+	sx, sy int
+	bound  int64
+}
+
+// findLargestRectangleStaircase is the staircase search behind Run
+func findLargestRectangleStaircase(redTiles []Point) int64 {
+	best, _ := searchStaircase(context.Background(), redTiles, nil)
+	return best
+}
+
+// searchStaircase is bestStaircase reduced to the area
+func searchStaircase(ctx context.Context, redTiles []Point, report progress.Func) (int64, error) {
+	rect, ok, err := bestStaircase(ctx, redTiles, report)
+	if !ok {
+		return 0, err
+	}
+	return rect.Area(), err
+}
+
+// bestStaircase tries the corners best bound first until no bound is left
+// that beats the best rectangle, and returns that rectangle
+func bestStaircase(ctx context.Context, redTiles []Point, report progress.Func) (geometry.Rect, bool, error) {
+	var winner geometry.Rect
+	if len(redTiles) < 4 {
+		return winner, false, nil
+	}
+	s := newStaircase(redTiles)
+
+	var candidates []candidate
+	for i, p := range redTiles {
+		row, okRow := runAt(s.rows, p.X, p.Y)
+		col, okCol := runAt(s.cols, p.Y, p.X)
+		if !okRow || !okCol {
+			continue
+		}
+		for _, sx := range []int{1, -1} {
+			for _, sy := range []int{1, -1} {
+				bound := int64(reach(row, p.X, sx)) * int64(reach(col, p.Y, sy))
+				candidates = append(candidates, candidate{i, sx, sy, bound})
+			}
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		switch {
+		case a.bound > b.bound:
+			return -1
+		case a.bound < b.bound:
+			return 1
+		}
+		return 0
+	})
+
+	var best int64
+	for i, c := range candidates {
+		if c.bound <= best {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return winner, best > 0, err
+		}
+		p := redTiles[c.index]
+		if area, q, ok := s.climb(p, c.sx, c.sy, best); ok {
+			best = area
+			winner = geometry.Rect{A: p, B: q}
+		}
+		report.Send(progress.Report{Done: i + 1, Total: len(candidates), Best: best})
+	}
+	report.Send(progress.Report{Done: len(candidates), Total: len(candidates), Best: best})
+	return winner, best > 0, nil
+}
+
+func newStaircase(redTiles []Point) *staircase {
+	transposed := make([]Point, len(redTiles))
+	for i, p := range redTiles {
+		transposed[i] = Point{X: p.Y, Y: p.X}
+	}
+	s := &staircase{
+		rows:  geometry.NewRectilinearPolygon(redTiles),
+		cols:  geometry.NewRectilinearPolygon(transposed),
+		edges: newEdgeIndex(redTiles),
+	}
+	s.rowXs = make([][]int, len(s.rows.YCoords))
+	for _, p := range redTiles {
+		k := sort.SearchInts(s.rows.YCoords, p.Y)
+		s.rowXs[k] = append(s.rowXs[k], p.X)
+	}
+	for _, xs := range s.rowXs {
+		sort.Ints(xs)
+	}
+	return s
+}
+
+// climb walks the rows from red tile p into quadrant (sx, sy) and returns
+// the largest valid area found with its far corner, if it beats best
+func (s *staircase) climb(p Point, sx, sy int, best int64) (int64, Point, bool) {
+	var corner Point
+	found := false
+	col, _ := runAt(s.cols, p.Y, p.X)
+	height := int64(reach(col, p.Y, sy))
+	bands := s.rows.Bands
+
+	// width counts the tiles from p.x out to W, both included
+	row, _ := runAt(s.rows, p.X, p.Y)
+	width := reach(row, p.X, sx)
+	k, _ := s.rows.BandAt(p.Y)
+	y := p.Y
+	for {
+		// A vertex row: cut W down to its run, then try its furthest red tile
+		run, ok := bands[k].Tiles.RangeOf(p.X)
+		if !ok {
+			break
+		}
+		width = min(width, reach(run, p.X, sx))
+		if x, ok := furthest(s.rowXs[sort.SearchInts(s.rows.YCoords, y)], p.X, sx, width); ok && (x != p.X || y != p.Y) {
+			area := int64(geometry.Abs(x-p.X)+1) * int64(geometry.Abs(y-p.Y)+1)
+			if area > best {
+				best, corner, found = area, Point{X: x, Y: y}, true
+			}
+		}
+
+		// The next band is either the next vertex row or the rows up to it
+		k += sy
+		if k < 0 || k >= len(bands) {
+			break
+		}
+		if _, vertexRow := slices.BinarySearch(s.rows.YCoords, bands[k].YMin); vertexRow {
+			y = bands[k].YMin
+			continue
+		}
+		run, ok = bands[k].Tiles.RangeOf(p.X)
+		if !ok {
+			break
+		}
+		width = min(width, reach(run, p.X, sx))
+		if int64(width)*height <= best {
+			break
+		}
+
+		// Past these rows the run only changes on a row with a horizontal
+		// edge across it
+		lo, hi := p.X, p.X+width-1
+		if sx < 0 {
+			lo, hi = p.X-width+1, p.X
+		}
+		if y, ok = s.edges.next(lo, hi, y, sy); !ok {
+			break
+		}
+		k, _ = s.rows.BandAt(y)
+	}
+	return best, corner, found
+}
+
+// runAt is the run of tiles on row y of poly holding x
+func runAt(poly *geometry.RectilinearPolygon, x, y int) (interval.Range[int], bool) {
+	k, ok := poly.BandAt(y)
+	if !ok {
+		return interval.Range[int]{}, false
+	}
+	return poly.Bands[k].Tiles.RangeOf(x)
+}
+
+// reach counts the tiles of run from v to its end in direction dir, both
+// included
+func reach(run interval.Range[int], v, dir int) int {
+	if dir > 0 {
+		return run.Hi - v + 1
+	}
+	return v - run.Lo + 1
+}
+
+// furthest is the x in xs furthest from x0 in direction dir, at most width
+// tiles away counting x0
+func furthest(xs []int, x0, dir, width int) (int, bool) {
+	if dir > 0 {
+		i := sort.SearchInts(xs, x0+width) - 1
+		return xs[max(i, 0)], i >= 0 && xs[i] >= x0
+	}
+	i := sort.SearchInts(xs, x0-width+1)
+	return xs[min(i, len(xs)-1)], i < len(xs) && xs[i] <= x0
+}
+
+// edgeIndex finds horizontal edges by the x they share with a range. It is
+// a segment tree over the vertex x coordinates; each node holds, sorted,
+// the rows of the edges covering it whole and of those reaching into it.
+type edgeIndex struct {
+	xs           []int
+	cover, reach [][]int
+}
+
+func newEdgeIndex(vertices []Point) *edgeIndex {
+	xs := make([]int, 0, len(vertices))
+	for _, p := range vertices {
+		xs = append(xs, p.X)
+	}
+	slices.Sort(xs)
+	xs = slices.Compact(xs)
+
+	idx := &edgeIndex{xs: xs, cover: make([][]int, 4*len(xs)), reach: make([][]int, 4*len(xs))}
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		if a.Y != b.Y {
+			continue
+		}
+		l := sort.SearchInts(xs, min(a.X, b.X))
+		r := sort.SearchInts(xs, max(a.X, b.X))
+		idx.insert(1, 0, len(xs)-1, l, r, a.Y)
+	}
+	for i := range idx.cover {
+		slices.Sort(idx.cover[i])
+		slices.Sort(idx.reach[i])
+	}
+	return idx
+}
+
+// insert adds an edge on row y over xs[l] to xs[r] below node, which spans
+// xs[lo] to xs[hi]
+func (idx *edgeIndex) insert(node, lo, hi, l, r, y int) {
+	if l <= lo && hi <= r {
+		idx.cover[node] = append(idx.cover[node], y)
+		return
+	}
+	idx.reach[node] = append(idx.reach[node], y)
+	mid := (lo + hi) / 2
+	if l <= mid {
+		idx.insert(2*node, lo, mid, l, r, y)
+	}
+	if r > mid {
+		idx.insert(2*node+1, mid+1, hi, l, r, y)
+	}
+}
+
+// next is the first row past y in direction dir with a horizontal edge
+// sharing an x with lo..hi; lo or hi must be a vertex x
+func (idx *edgeIndex) next(lo, hi, y, dir int) (int, bool) {
+	l := sort.SearchInts(idx.xs, lo)
+	r := sort.SearchInts(idx.xs, hi+1) - 1
+	found, ok := 0, false
+	var query func(node, nodeLo, nodeHi int)
+	query = func(node, nodeLo, nodeHi int) {
+		if r < nodeLo || nodeHi < l {
+			return
+		}
+		rows := [][]int{idx.cover[node]}
+		if l <= nodeLo && nodeHi <= r {
+			rows = append(rows, idx.reach[node])
+		}
+		for _, ys := range rows {
+			if dir > 0 {
+				if i := sort.SearchInts(ys, y+1); i < len(ys) && (!ok || ys[i] < found) {
+					found, ok = ys[i], true
+				}
+			} else if i := sort.SearchInts(ys, y) - 1; i >= 0 && (!ok || ys[i] > found) {
+				found, ok = ys[i], true
+			}
+		}
+		if l <= nodeLo && nodeHi <= r || nodeLo == nodeHi {
+			return
+		}
+		mid := (nodeLo + nodeHi) / 2
+		query(2*node, nodeLo, mid)
+		query(2*node+1, mid+1, nodeHi)
+	}
+	if len(idx.xs) > 0 {
+		query(1, 0, len(idx.xs)-1)
+	}
+	return found, ok
+}
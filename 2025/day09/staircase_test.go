@@ -0,0 +1,87 @@
+package day09
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"aoc/pkg/geometry"
+	"aoc/pkg/polygen"
+)
+
+func TestStaircaseExample(t *testing.T) {
+	redTiles := []Point{
+		{X: 7, Y: 1}, {X: 11, Y: 1}, {X: 11, Y: 7}, {X: 9, Y: 7}, {X: 9, Y: 5},
+		{X: 2, Y: 5}, {X: 2, Y: 3}, {X: 7, Y: 3},
+	}
+	if got := findLargestRectangleStaircase(redTiles); got != 24 {
+		t.Errorf("expected 24, got %d", got)
+	}
+	// The same loop run the other way round
+	reversed := make([]Point, len(redTiles))
+	for i, p := range redTiles {
+		reversed[len(redTiles)-1-i] = p
+	}
+	if got := findLargestRectangleStaircase(reversed); got != 24 {
+		t.Errorf("reversed: expected 24, got %d", got)
+	}
+}
+
+// TestStaircaseMatchesCompressedGrid compares the two searches on random
+// polygons, half of them with edges a tile apart
+func TestStaircaseMatchesCompressedGrid(t *testing.T) {
+	rng := rand.New(rand.NewSource(36))
+	for round := 0; round < 300; round++ {
+		redTiles, err := polygen.Generate(polygen.Options{
+			Seed:        int64(round),
+			Shape:       polygen.Shape(round % 3),
+			Vertices:    4 + rng.Intn(60),
+			MaxCoord:    400,
+			OneTileGaps: round%2 == 1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := findLargestRectangleOptimized(redTiles)
+		if got := findLargestRectangleStaircase(redTiles); got != want {
+			t.Fatalf("polygon %v: staircase %d, compressed grid %d", redTiles, got, want)
+		}
+	}
+}
+
+func TestStaircaseRealInput(t *testing.T) {
+	redTiles, _, err := geometry.ReadPolygon("input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := findLargestRectangleStaircase(redTiles); got != 1539809693 {
+		t.Errorf("expected 1539809693, got %d", got)
+	}
+}
+
+func BenchmarkFindLargestRectangleStaircase(b *testing.B) {
+	for _, shape := range []polygen.Shape{polygen.Notched, polygen.Corridor} {
+		for _, vertices := range []int{496, 5000, 50000} {
+			redTiles, err := polygen.Generate(polygen.Options{Seed: 1, Shape: shape, Vertices: vertices, MaxCoord: 1000000})
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Run(fmt.Sprintf("%v/%d", shape, vertices), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					findLargestRectangleStaircase(redTiles)
+				}
+			})
+		}
+	}
+	for _, vertices := range []int{496, 2000} {
+		redTiles, err := polygen.Generate(polygen.Options{Seed: 1, Shape: polygen.Spiral, Vertices: vertices, MaxCoord: 1000000})
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("spiral/%d", vertices), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				findLargestRectangleStaircase(redTiles)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package day09
+
+import (
+	"container/heap"
+	"slices"
+
+	"aoc/pkg/geometry"
+	"aoc/pkg/viz"
+)
+
+// WriteSVG draws the polygon of redTiles into filename with the winning
+// rectangle on top and, when top > 0, the top largest rectangles between
+// red tiles whether they fit or not, so rejected ones can be inspected
+func WriteSVG(filename string, redTiles []Point, top int) error {
+	opts := viz.SVGOptions{Candidates: TopRectangles(redTiles, top)}
+	if winner, ok := BestRectangle(redTiles); ok {
+		opts.Winner = &winner
+	}
+	return viz.WritePolygonSVGFile(filename, geometry.NewRectilinearPolygon(redTiles), opts)
+}
+
+// TopRectangles returns the n largest rectangles with red corners, largest
+// first, without checking that they fit inside
+func TopRectangles(redTiles []Point, n int) []geometry.Rect {
+	if n <= 0 {
+		return nil
+	}
+	h := &rectHeap{}
+	for i, p1 := range redTiles {
+		for _, p2 := range redTiles[i+1:] {
+			r := geometry.Rect{A: p1, B: p2}
+			if h.Len() < n {
+				heap.Push(h, r)
+			} else if r.Area() > (*h)[0].Area() {
+				(*h)[0] = r
+				heap.Fix(h, 0)
+			}
+		}
+	}
+	rects := []geometry.Rect(*h)
+	slices.SortStableFunc(rects, func(a, b geometry.Rect) int {
+		switch {
+		case a.Area() > b.Area():
+			return -1
+		case a.Area() < b.Area():
+			return 1
+		}
+		return 0
+	})
+	return rects
+}
+
+// rectHeap is a min-heap by area
+type rectHeap []geometry.Rect
+
+func (h rectHeap) Len() int           { return len(h) }
+func (h rectHeap) Less(i, j int) bool { return h[i].Area() < h[j].Area() }
+func (h rectHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *rectHeap) Push(x any)        { *h = append(*h, x.(geometry.Rect)) }
+func (h *rectHeap) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
@@ -0,0 +1,71 @@
+package day09
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aoc/pkg/geometry"
+)
+
+var svgExample = []Point{
+	{X: 7, Y: 1}, {X: 11, Y: 1}, {X: 11, Y: 7}, {X: 9, Y: 7}, {X: 9, Y: 5},
+	{X: 2, Y: 5}, {X: 2, Y: 3}, {X: 7, Y: 3},
+}
+
+func TestBestRectangle(t *testing.T) {
+	rect, ok := BestRectangle(svgExample)
+	if !ok || rect.Area() != 24 {
+		t.Fatalf("got %v, %v; expected an area 24 rectangle", rect, ok)
+	}
+	if !geometry.NewRectilinearPolygon(svgExample).ContainsTiles(rect.A, rect.B) {
+		t.Errorf("%v does not fit", rect)
+	}
+}
+
+func TestTopRectangles(t *testing.T) {
+	top := TopRectangles(svgExample, 3)
+	if len(top) != 3 {
+		t.Fatalf("got %d rectangles", len(top))
+	}
+	// 2,5 to 11,1 is the area 50 rectangle from the part 1 example
+	if top[0].Area() != 50 {
+		t.Errorf("largest is %v with area %d, expected 50", top[0], top[0].Area())
+	}
+	for i := 1; i < len(top); i++ {
+		if top[i].Area() > top[i-1].Area() {
+			t.Errorf("not sorted: %v", top)
+		}
+	}
+	if got := TopRectangles(svgExample, 0); got != nil {
+		t.Errorf("expected nothing for n = 0, got %v", got)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "day09.svg")
+	if err := WriteSVG(filename, svgExample, 5); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := string(data)
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		`<polygon id="boundary"`,
+		`points="7,1 11,1 11,7 9,7 9,5 2,5 2,3 7,3"`,
+		`<rect id="winner"`,
+		`>24</text>`,
+		`>50</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG lacks %q", want)
+		}
+	}
+	if n := strings.Count(svg, `<circle`); n != len(svgExample) {
+		t.Errorf("got %d red tiles, expected %d", n, len(svgExample))
+	}
+}
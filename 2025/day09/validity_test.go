@@ -0,0 +1,89 @@
+package day09
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"aoc/pkg/geometry"
+	"aoc/pkg/polygen"
+)
+
+// TestBoundaryGridMatchesTiles compares the frozen boundary with the tile
+// by tile boundary from identifyRedGreenTiles
+func TestBoundaryGridMatchesTiles(t *testing.T) {
+	rng := rand.New(rand.NewSource(47))
+	for round := 0; round < 50; round++ {
+		redTiles, err := polygen.Generate(polygen.Options{Seed: rng.Int63(), Shape: polygen.Shape(rng.Intn(3)), Vertices: 4 + 2*rng.Intn(15), MaxCoord: 60})
+		if err != nil {
+			t.Fatal(err)
+		}
+		grid := geometry.BoundaryGrid(redTiles)
+		tiles := identifyRedGreenTiles(redTiles)
+		minX, maxX, minY, maxY := getBounds(redTiles)
+		for x := minX - 1; x <= maxX+1; x++ {
+			for y := minY - 1; y <= maxY+1; y++ {
+				if grid.Get(x, y) != tiles[Point{X: x, Y: y}] {
+					t.Fatalf("polygon %v: tile %d,%d grid %v, want %v", redTiles, x, y, grid.Get(x, y), tiles[Point{X: x, Y: y}])
+				}
+			}
+		}
+	}
+}
+
+// lockedGrid is the map behind a read-write lock that ValidityGrid used to
+// be, kept to benchmark against
+type lockedGrid struct {
+	data map[int]map[int]bool
+	mu   sync.RWMutex
+}
+
+func (g *lockedGrid) Get(x, y int) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.data[x][y]
+}
+
+// BenchmarkValidityGridGet reads the boundary of a generated polygon from
+// every CPU at once. The locked map makes every reader bump the same lock
+// word; the frozen grid shares nothing that is written.
+func BenchmarkValidityGridGet(b *testing.B) {
+	redTiles, err := polygen.Generate(polygen.Options{Seed: 1, Shape: polygen.Notched, Vertices: 496, MaxCoord: 2000})
+	if err != nil {
+		b.Fatal(err)
+	}
+	frozen := geometry.BoundaryGrid(redTiles)
+	locked := &lockedGrid{data: make(map[int]map[int]bool)}
+	for p := range identifyRedGreenTiles(redTiles) {
+		if locked.data[p.X] == nil {
+			locked.data[p.X] = make(map[int]bool)
+		}
+		locked.data[p.X][p.Y] = true
+	}
+	minX, maxX, minY, maxY := getBounds(redTiles)
+
+	grids := []struct {
+		name string
+		get  func(x, y int) bool
+	}{
+		{"locked", locked.Get},
+		{"frozen", frozen.Get},
+	}
+	for _, g := range grids {
+		b.Run(g.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				rng := rand.New(rand.NewSource(rand.Int63()))
+				for pb.Next() {
+					g.get(minX+rng.Intn(maxX-minX+1), minY+rng.Intn(maxY-minY+1))
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkBuildBoundaryGrid(b *testing.B) {
+	redTiles := parseInput("input.txt")
+	for i := 0; i < b.N; i++ {
+		geometry.BoundaryGrid(redTiles)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"aoc/pkg/gf2"
+)
+
+// Machine is one line of the manual: the indicator lights to switch on, the
+// buttons with the lights each one toggles, and the joltage requirements
+type Machine struct {
+	Lights  int        // number of indicator lights
+	Target  gf2.Vector // bit i is set when light i must end up on
+	Buttons [][]int
+	Joltage []int
+}
+
+// Button is the lights toggled by button b as a vector
+func (m Machine) Button(b int) gf2.Vector {
+	v := gf2.NewVector(m.Lights)
+	for _, light := range m.Buttons[b] {
+		v.Set(light)
+	}
+	return v
+}
+
+// parseMachine reads "[.##.] (3) (1,3) ... {3,5,4,7}"
+func parseMachine(line string) (Machine, error) {
+	var m Machine
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return m, fmt.Errorf("want [lights] (buttons)... {joltage}, got %q", line)
+	}
+
+	lights := fields[0]
+	if !strings.HasPrefix(lights, "[") || !strings.HasSuffix(lights, "]") {
+		return m, fmt.Errorf("want [lights], got %q", lights)
+	}
+	lights = lights[1 : len(lights)-1]
+	m.Lights = len(lights)
+	m.Target = gf2.NewVector(m.Lights)
+	for i, c := range lights {
+		switch c {
+		case '#':
+			m.Target.Set(i)
+		case '.':
+		default:
+			return m, fmt.Errorf("light %d is %q, want . or #", i, c)
+		}
+	}
+
+	for _, f := range fields[1:] {
+		switch {
+		case strings.HasPrefix(f, "(") && strings.HasSuffix(f, ")"):
+			if m.Joltage != nil {
+				return m, fmt.Errorf("button %s after the joltage", f)
+			}
+			wiring, err := parseInts(f[1 : len(f)-1])
+			if err != nil {
+				return m, fmt.Errorf("button %s: %w", f, err)
+			}
+			for _, light := range wiring {
+				if light < 0 || light >= m.Lights {
+					return m, fmt.Errorf("button %s: no light %d", f, light)
+				}
+			}
+			m.Buttons = append(m.Buttons, wiring)
+		case strings.HasPrefix(f, "{") && strings.HasSuffix(f, "}"):
+			joltage, err := parseInts(f[1 : len(f)-1])
+			if err != nil {
+				return m, fmt.Errorf("joltage %s: %w", f, err)
+			}
+			if len(joltage) != m.Lights {
+				return m, fmt.Errorf("joltage %s: want %d values, one per light", f, m.Lights)
+			}
+			m.Joltage = joltage
+		default:
+			return m, fmt.Errorf("unexpected %q", f)
+		}
+	}
+	return m, nil
+}
+
+func parseInts(s string) ([]int, error) {
+	var ints []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
+
+// parseMachines reads one machine per line, skipping blank lines
+func parseMachines(r io.Reader) ([]Machine, error) {
+	var machines []Machine
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		m, err := parseMachine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		machines = append(machines, m)
+	}
+	return machines, scanner.Err()
+}
+
+func readMachines(filename string) ([]Machine, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	machines, err := parseMachines(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return machines, nil
+}
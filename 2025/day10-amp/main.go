@@ -2,17 +2,143 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"strings"
+
+	"aoc/pkg/bfs"
+	"aoc/pkg/gf2"
+	"aoc/pkg/ilp"
 )
 
 func main() {
 	fmt.Println("Welcome to Day 10!!")
-	run("input.txt")
+	if err := run("input.txt"); err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err)
+		os.Exit(1)
+	}
+}
+
+func run(input string) error {
+	machines, err := readMachines(input)
+	if err != nil {
+		return err
+	}
+	presses, err := part1(machines)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Part 1:", presses)
+	presses, err = part2(machines)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Part 2:", presses)
+	return nil
+}
+
+// part1 sums the fewest button presses that light up every machine
+func part1(machines []Machine) (int, error) {
+	total := 0
+	for i, m := range machines {
+		presses, ok := fewestPresses(m)
+		if !ok {
+			return 0, fmt.Errorf("machine %d: no presses light up %s", i+1, formatLights(m.Target, m.Lights))
+		}
+		total += presses
+	}
+	return total, nil
 }
 
-func run(input string) {
-	fmt.Println("run!!")
-	case1()
+// fewestPresses solves the machine as a linear system over GF(2): one
+// equation per light, one unknown per button, since pressing a button twice
+// undoes it. The fewest presses is the solution with the fewest ones; ok is
+// false when no set of buttons reaches the target.
+func fewestPresses(m Machine) (presses int, ok bool) {
+	system := gf2.NewMatrix(m.Lights, len(m.Buttons))
+	for b, wiring := range m.Buttons {
+		for _, light := range wiring {
+			system.Set(light, b)
+		}
+	}
+	x, err := system.MinWeightSolution(m.Target)
+	if err != nil {
+		return 0, false
+	}
+	return x.OnesCount(), true
+}
+
+// pressSequence searches every light pattern breadth first for a shortest
+// sequence of buttons to press. It is exponential in the lights, so it only
+// serves to check fewestPresses on machines with a few lights.
+func pressSequence(m Machine) ([]int, bool) {
+	return bfs.Shortest(m.Lights, len(m.Buttons), lightMask(m.Target.Ones()), bfs.XOR(buttonMasks(m)))
+}
+
+func buttonMasks(m Machine) []int {
+	masks := make([]int, len(m.Buttons))
+	for b, wiring := range m.Buttons {
+		masks[b] = lightMask(wiring)
+	}
+	return masks
+}
+
+// lightMask packs a few light numbers into a bitmask for the search
+func lightMask(lights []int) int {
+	mask := 0
+	for _, light := range lights {
+		mask |= 1 << light
+	}
+	return mask
+}
+
+// part2 sums the fewest button presses that bring every machine's joltage
+// counters to their targets
+func part2(machines []Machine) (int, error) {
+	total := 0
+	for i, m := range machines {
+		presses, err := fewestJoltagePresses(m)
+		if err != nil {
+			return 0, fmt.Errorf("machine %d: %w", i+1, err)
+		}
+		total += presses
+	}
+	return total, nil
+}
+
+// fewestJoltagePresses is an integer linear program: one equation per
+// counter, one unknown per button counting its presses, and the total
+// presses to minimise. A counter never goes down, so each button is pressed
+// at most the smallest target among its counters.
+func fewestJoltagePresses(m Machine) (int, error) {
+	a := make([][]int, len(m.Joltage))
+	for c := range a {
+		a[c] = make([]int, len(m.Buttons))
+	}
+	for b, wiring := range m.Buttons {
+		for _, counter := range wiring {
+			a[counter][b] = 1
+		}
+	}
+	sol, err := ilp.Solve(ilp.Problem{A: a, B: m.Joltage})
+	if err != nil {
+		return 0, err
+	}
+	return sol.Cost, nil
+}
+
+// formatLights draws a light pattern the way the manual does, light 0 first
+func formatLights(state gf2.Vector, lights int) string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i := 0; i < lights; i++ {
+		if state.Bit(i) {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	sb.WriteByte(']')
+	return sb.String()
 }
 
 // formatIntsAsBinary takes a slice of integers and returns a space-separated
@@ -26,30 +152,3 @@ func formatIntsAsBinary(inputs []int) string {
 	}
 	return strings.Join(binaryParts, " ")
 }
-
-func showButton() {
-	// Example input: an integer slice representing the values 1, 5, 2, 3, 10, 12
-	// Note: these must be within the range representable by 4 bits (0 to 15)
-	inputData := []int{1, 5, 2, 3, 10, 12}
-
-	for _, i := range inputData {
-		out := fmt.Sprintf("%04b", i)
-		fmt.Println("INT:", i, "OUT:", out)
-	}
-	// Get the formatted binary string
-	binaryOutput := formatIntsAsBinary(inputData)
-
-	// Print the final output
-	fmt.Printf("Button: %s\n", binaryOutput)
-}
-
-func case1() {
-	fmt.Println("case1!!")
-	showButton()
-	// [.##.] (3) (1,3) (2) (2,3) (0,2) (0,1)
-	// Start with target and work backwards - 0110 == 6
-	// Button: 0001 0101 0010 0011 1010 1100
-
-	// Ends with 6, always start with 0
-	// Depth search 4; call each button combo; recursive?
-}
@@ -1,20 +1,181 @@
 package main
 
-import "testing"
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"aoc/pkg/gf2"
+)
+
+// sampleLine is the first machine of the puzzle example
+const sampleLine = "[.##.] (3) (1,3) (2) (2,3) (0,2) (0,1) {3,5,4,7}"
 
 func Test_run(t *testing.T) {
 	type args struct {
 		input string
 	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"happy", args{"test.txt"}, false},
+		{"missing", args{"missing.txt"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := run(tt.args.input); (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_parseMachine(t *testing.T) {
+	m, err := parseMachine(sampleLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := gf2.NewVector(4)
+	target.Set(1)
+	target.Set(2)
+	want := Machine{
+		Lights:  4,
+		Target:  target,
+		Buttons: [][]int{{3}, {1, 3}, {2}, {2, 3}, {0, 2}, {0, 1}},
+		Joltage: []int{3, 5, 4, 7},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %+v, want %+v", m, want)
+	}
+	if got := m.Button(1).Ones(); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("Button(1) lights %v, want [1 3]", got)
+	}
+}
+
+func Test_parseMachineErrors(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"[.##.]", "want [lights] (buttons)"},
+		{".##. (1)", "want [lights]"},
+		{"[.x#.] (1)", `light 1 is 'x'`},
+		{"[.##.] (1,a)", "button (1,a)"},
+		{"[.##.] (4)", "no light 4"},
+		{"[.##.] (1) {1,2}", "want 4 values"},
+		{"[.##.] {1,2,3,4} (1)", "after the joltage"},
+		{"[.##.] (1) <2>", `unexpected "<2>"`},
+	}
+	for _, tt := range tests {
+		_, err := parseMachine(tt.line)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseMachine(%q) = %v, want an error mentioning %q", tt.line, err, tt.want)
+		}
+	}
+}
+
+func Test_parseMachinesLineNumbers(t *testing.T) {
+	_, err := parseMachines(strings.NewReader(sampleLine + "\n\n[#] (2)\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("got %v, want an error on line 3", err)
+	}
+}
+
+func Test_fewestPresses(t *testing.T) {
 	tests := []struct {
 		name string
-		args args
+		line string
+		want int
+		ok   bool
 	}{
-		{"happy", args{"test.txt"}},
+		// (0,2) then (0,1) switches on lights 1 and 2
+		{"sample", sampleLine, 2, true},
+		{"already lit", "[....] (0) (1)", 0, true},
+		{"one button", "[.#.] (0) (1) (2)", 1, true},
+		{"two presses", "[###] (0,1) (1,2) (2)", 2, true},
+		{"unreachable", "[#..] (1) (1,2)", 0, false},
+		// Past one word of lights: 70 and 71 light up together
+		{"many lights", "[" + strings.Repeat(".", 70) + "##] (0,70) (70,71) (71)", 1, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			run(tt.args.input)
+			m, err := parseMachine(tt.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := fewestPresses(m)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("fewestPresses = %d, %v; want %d, %v", got, ok, tt.want, tt.ok)
+			}
 		})
 	}
 }
+
+func Test_pressSequenceAgrees(t *testing.T) {
+	machines, err := readMachines("input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range machines {
+		want, wantOK := fewestPresses(m)
+		seq, ok := pressSequence(m)
+		if ok != wantOK || len(seq) != want {
+			t.Errorf("machine %d: BFS presses %v, GF(2) %d", i+1, seq, want)
+			continue
+		}
+		lights := gf2.NewVector(m.Lights)
+		for _, b := range seq {
+			lights.Xor(m.Button(b))
+		}
+		if !slices.Equal(lights, m.Target) {
+			t.Errorf("machine %d: buttons %s pressed %v light %s", i+1,
+				formatIntsAsBinary(buttonMasks(m)), seq, formatLights(lights, m.Lights))
+		}
+	}
+}
+
+func Test_part1(t *testing.T) {
+	machines, err := readMachines("test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := part1(machines); err != nil || got != 7 {
+		t.Errorf("part1 = %d, %v; want 7", got, err)
+	}
+
+	unreachable, _ := parseMachine("[#..] (1) (1,2)")
+	if _, err := part1([]Machine{unreachable}); err == nil || !strings.Contains(err.Error(), "[#..]") {
+		t.Errorf("got %v, want an error naming the pattern", err)
+	}
+}
+
+func Test_fewestJoltagePresses(t *testing.T) {
+	machines, err := readMachines("test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{10, 12, 11} {
+		if got, err := fewestJoltagePresses(machines[i]); err != nil || got != want {
+			t.Errorf("machine %d: got %d, %v; want %d", i+1, got, err, want)
+		}
+	}
+	if got, err := part2(machines); err != nil || got != 33 {
+		t.Errorf("part2 = %d, %v; want 33", got, err)
+	}
+
+	// Two counters that must differ but every button bumps both
+	stuck, _ := parseMachine("[..] (0,1) {2,3}")
+	if _, err := part2([]Machine{stuck}); err == nil || !strings.HasPrefix(err.Error(), "machine 1:") {
+		t.Errorf("got %v, want an error for machine 1", err)
+	}
+}
+
+func Test_formatLights(t *testing.T) {
+	m, _ := parseMachine(sampleLine)
+	if got := formatLights(m.Target, 4); got != "[.##.]" {
+		t.Errorf("got %s", got)
+	}
+}
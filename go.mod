@@ -5,7 +5,6 @@ go 1.23.12
 require (
 	github.com/bitfield/script v0.24.1
 	github.com/davecgh/go-spew v1.1.1
-	github.com/emirpasic/gods v1.18.1
 )
 
 require (
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"aoc/2021/day11"
+	"aoc/2022/day05"
+	"aoc/2025/day09"
+	day09opus "aoc/2025/day09-opus"
+	"aoc/pkg/geometry"
+	"aoc/pkg/harness"
+	"aoc/pkg/polygen"
+	"aoc/pkg/progress"
+	"aoc/pkg/viz"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "ERR:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the flags and runs the chosen puzzle
+func run() error {
+	year := flag.Int("year", 2022, "puzzle year")
+	day := flag.Int("day", 5, "puzzle day")
+	part := flag.Int("part", 1, "puzzle part")
+	input := flag.String("input", "", "puzzle input (defaults to the day's testdata)")
+	visualize := flag.Bool("visualize", false, "animate the puzzle state in the terminal")
+	delay := flag.Duration("delay", 200*time.Millisecond, "pause between frames")
+	every := flag.Int("every", 1, "render one frame every N steps")
+	frames := flag.String("frames", "", "headless: write the frames to this file instead of animating")
+	steps := flag.Int("steps", 100, "steps to simulate (2021 day 11)")
+	impl := flag.String("impl", "", "which of several solutions to run (2025 day 9: staircase, grid, slabs)")
+	compare := flag.Bool("compare", false, "run every registered solution on the input and compare answers")
+	workers := flag.Int("workers", 0, "search goroutines for solvers that take them (0 uses every CPU)")
+	timing := flag.Bool("timing", false, "print how long each phase of the solve took")
+	svg := flag.String("svg", "", "2025 day 9: also draw the polygon and winning rectangle to this SVG file")
+	top := flag.Int("top", 0, "2025 day 9: with -svg, also draw the N largest candidate rectangles")
+	timeout := flag.Duration("timeout", 0, "give up on long-running solvers after this long (0 waits forever)")
+	generate := flag.String("generate", "", "write a random 2025 day 9 puzzle input to this file and exit")
+	shape := flag.String("shape", "notched", "generated polygon shape (notched, spiral, corridor)")
+	vertices := flag.Int("vertices", 496, "minimum vertices of the generated polygon")
+	seed := flag.Int64("seed", 1, "seed for the generated polygon")
+	maxCoord := flag.Int("maxcoord", 100000, "largest coordinate of the generated polygon")
+	tileGaps := flag.Bool("tilegaps", false, "put some parallel edges of the generated polygon a tile apart")
+	flag.Parse()
+
+	if *generate != "" {
+		return generateInput(*generate, *shape, *vertices, *seed, *maxCoord, *tileGaps)
+	}
+
+	fmt.Printf("AoC %d Day %02d Part %d ..\n", *year, *day, *part)
+
+	var anim *viz.Animator
+	if *visualize || *frames != "" {
+		opts := viz.Options{Delay: *delay, Every: *every}
+		var w io.Writer = os.Stdout
+		if *frames != "" {
+			f, err := os.Create(*frames)
+			if err != nil {
+				return fmt.Errorf("frames: %w", err)
+			}
+			defer f.Close()
+			w = f
+			anim = viz.NewHeadless(w, opts)
+		} else {
+			anim = viz.NewTerminal(w, opts)
+		}
+	}
+
+	var err error
+	switch {
+	case *year == 2021 && *day == 11:
+		file := inputOr(*input, "2021/day11/testdata/sample.txt")
+		switch {
+		case anim != nil:
+			err = day11.Visualize(file, *steps, anim)
+		case *part == 1:
+			fmt.Println(day11.Part1(file, *steps))
+		default:
+			fmt.Println(day11.Part2(file))
+		}
+	case *year == 2022 && *day == 5:
+		file := inputOr(*input, "2022/day05/testdata/input.txt")
+		switch {
+		case anim != nil:
+			err = day05.Visualize(file, *part == 2, anim)
+		case *part == 1:
+			err = day05.Part1(file)
+			fmt.Println()
+		default:
+			err = day05.Part2(file)
+			fmt.Println()
+		}
+	case *year == 2025 && *day == 9:
+		file := inputOr(*input, "2025/day09/input.txt")
+		// Part 1 has a single solver, so the flags that pick and tune a
+		// part 2 solver would quietly do nothing
+		var part2Only []string
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "impl", "timeout", "workers", "timing":
+				part2Only = append(part2Only, "-"+f.Name)
+			}
+		})
+		switch {
+		case *compare:
+			err = compareAll(rectangles, day09Part2, file)
+		case *part == 1 && len(part2Only) > 0:
+			err = fmt.Errorf("%s only apply to part 2 of 2025 day 9", strings.Join(part2Only, ", "))
+		case *part == 1:
+			fmt.Println("Answer:", day09.RunPart(file, 1))
+		case *impl == "slabs":
+			err = solveWithStatus(*timeout, "slabs", func(ctx context.Context, report progress.Func) (int64, error) {
+				opts := day09opus.Options{Input: file, Workers: *workers, Timing: *timing, Progress: report}
+				return day09opus.Solve(ctx, opts)
+			})
+		case *impl == "" || *impl == "staircase":
+			err = solveWithStatus(*timeout, "staircase", func(ctx context.Context, report progress.Func) (int64, error) {
+				points, _, err := geometry.ReadPolygon(file)
+				if err != nil {
+					return 0, err
+				}
+				return day09.LargestRectangleContext(ctx, points, report)
+			})
+		case *impl == "grid":
+			err = solveWithStatus(*timeout, "grid", func(ctx context.Context, report progress.Func) (int64, error) {
+				points, _, err := geometry.ReadPolygon(file)
+				if err != nil {
+					return 0, err
+				}
+				return day09.LargestRectangleGridContext(ctx, points, report)
+			})
+		default:
+			err = fmt.Errorf("unknown -impl %q for 2025 day 9", *impl)
+		}
+		if err == nil && *svg != "" {
+			err = writeSVG(*svg, file, *top)
+		}
+	default:
+		err = fmt.Errorf("no runner for %d day %d", *year, *day)
+	}
+	return err
+}
+
+// compareAll runs every solution registered for key on the polygon in file
+func compareAll(r *harness.Registry[[]geometry.Point, int64], key harness.Key, file string) error {
+	points, _, err := geometry.ReadPolygon(file)
+	if err != nil {
+		return err
+	}
+	if d := r.Compare(key, points); d != nil {
+		for _, res := range d.Results {
+			fmt.Printf("%s: %v\n", res.Name, res.Answer)
+		}
+		return fmt.Errorf("%v: solutions disagree", key)
+	}
+	for _, impl := range r.Implementations(key) {
+		fmt.Printf("%s: agrees\n", impl.Name)
+	}
+	return nil
+}
+
+// writeSVG draws the 2025 day 9 polygon in file with its best rectangle
+func writeSVG(svgFile, file string, top int) error {
+	points, _, err := geometry.ReadPolygon(file)
+	if err != nil {
+		return err
+	}
+	if err := day09.WriteSVG(svgFile, points, top); err != nil {
+		return err
+	}
+	fmt.Println("Wrote", svgFile)
+	return nil
+}
+
+// solveWithStatus runs a long solve, drawing its progress as a status line
+// on stderr, and prints the answer. When the timeout runs out the best
+// answer found so far is printed along with the error.
+func solveWithStatus(timeout time.Duration, label string, solve func(context.Context, progress.Func) (int64, error)) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	report, finish := progress.StatusLine(os.Stderr, label, 100*time.Millisecond)
+	answer, err := solve(ctx, report)
+	finish()
+	if err != nil {
+		fmt.Println("Best so far:", answer)
+		return err
+	}
+	fmt.Println("Answer:", answer)
+	return nil
+}
+
+// generateInput writes a random rectilinear polygon in the 2025 day 9
+// input format
+func generateInput(file, shape string, vertices int, seed int64, maxCoord int, tileGaps bool) error {
+	s, err := polygen.ParseShape(shape)
+	if err != nil {
+		return err
+	}
+	points, err := polygen.Generate(polygen.Options{Seed: seed, Shape: s, Vertices: vertices, MaxCoord: maxCoord, OneTileGaps: tileGaps})
+	if err != nil {
+		return err
+	}
+	return polygen.WriteFile(file, points)
+}
+
+func inputOr(input, fallback string) string {
+	if input != "" {
+		return input
+	}
+	return fallback
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"aoc/2025/day09"
+	"aoc/pkg/geometry"
+	"aoc/pkg/harness"
+	"aoc/pkg/polygen"
+)
+
+// randomPolygon generates a small polygon of any shape, half the time with
+// parallel edges a tile apart
+func randomPolygon(t *testing.T, rng *rand.Rand) []geometry.Point {
+	points, err := polygen.Generate(polygen.Options{
+		Seed:        rng.Int63(),
+		Shape:       polygen.Shape(rng.Intn(3)),
+		Vertices:    4 + rng.Intn(30),
+		MaxCoord:    200,
+		OneTileGaps: rng.Intn(2) == 0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return points
+}
+
+// cutCorner proposes smaller polygons made by taking out one edge b-c
+// between parallel edges a-b and c-d: a or d slides along its other edge
+// until a lines up with d, and corners left on a straight line go. Only
+// the candidates that are still valid polygons are kept.
+func cutCorner(points []geometry.Point) [][]geometry.Point {
+	var out [][]geometry.Point
+	n := len(points)
+	for i := 0; n > 4 && i < n; i++ {
+		a, b, d := points[i], points[(i+1)%n], points[(i+3)%n]
+		slideA, slideD := geometry.Point{X: a.X, Y: d.Y}, geometry.Point{X: d.X, Y: a.Y}
+		if a.X == b.X {
+			slideA, slideD = geometry.Point{X: d.X, Y: a.Y}, geometry.Point{X: a.X, Y: d.Y}
+		}
+		for _, move := range []struct {
+			at int
+			to geometry.Point
+		}{{i, slideA}, {(i + 3) % n, slideD}} {
+			candidate := slices.Clone(points)
+			candidate[move.at] = move.to
+			candidate = straighten(dropIndices(candidate, (i+1)%n, (i+2)%n))
+			if geometry.ValidatePolygon(candidate, nil) == nil {
+				out = append(out, candidate)
+			}
+		}
+	}
+	return out
+}
+
+// dropIndices returns points without the vertices at the given indices
+func dropIndices(points []geometry.Point, drop ...int) []geometry.Point {
+	var out []geometry.Point
+	for i, p := range points {
+		if !slices.Contains(drop, i) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// straighten removes repeated vertices and vertices in the middle of a
+// straight line until every vertex left is a corner
+func straighten(points []geometry.Point) []geometry.Point {
+	for changed := true; changed && len(points) > 2; {
+		changed = false
+		n := len(points)
+		for i, v := range points {
+			prev, next := points[(i+n-1)%n], points[(i+1)%n]
+			if v == prev || prev.X == v.X && v.X == next.X || prev.Y == v.Y && v.Y == next.Y {
+				points = slices.Delete(points, i, i+1)
+				changed = true
+				break
+			}
+		}
+	}
+	return points
+}
+
+// TestDay09SolutionsAgree runs both 2025 day 09 solutions on random
+// polygons and reports a shrunk counterexample when they differ
+func TestDay09SolutionsAgree(t *testing.T) {
+	d := rectangles.Check(day09Part2, harness.Config[[]geometry.Point]{
+		Seed:     9,
+		Rounds:   200,
+		Generate: func(rng *rand.Rand) []geometry.Point { return randomPolygon(t, rng) },
+		Shrink:   cutCorner,
+	})
+	if d != nil {
+		t.Fatal(d)
+	}
+}
+
+// TestDay09CheckShrinksDisagreement plants a solution that is one off on
+// polygons with more than eight vertices, feeds the harness a square with a
+// slit a tile wide and a bump on its side, and expects it to report the
+// disagreement and shrink the polygon as far as the bug allows
+func TestDay09CheckShrinksDisagreement(t *testing.T) {
+	bumpedSlit := []geometry.Point{
+		{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 6, Y: 10},
+		{X: 6, Y: 2}, {X: 5, Y: 2}, {X: 5, Y: 10}, {X: 0, Y: 10},
+		{X: 0, Y: 7}, {X: -3, Y: 7}, {X: -3, Y: 4}, {X: 0, Y: 4},
+	}
+	r := harness.NewRegistry[[]geometry.Point, int64]()
+	r.Register(day09Part2, "staircase", day09.LargestRectangle)
+	r.Register(day09Part2, "off by one", func(redTiles []geometry.Point) int64 {
+		answer := day09.LargestRectangleGrid(redTiles)
+		if len(redTiles) > 8 {
+			answer++
+		}
+		return answer
+	})
+	d := r.Check(day09Part2, harness.Config[[]geometry.Point]{
+		Rounds:   1,
+		Generate: func(*rand.Rand) []geometry.Point { return bumpedSlit },
+		Shrink:   cutCorner,
+	})
+	if d == nil {
+		t.Fatal("the planted bug went unnoticed")
+	}
+	if d.Shrinks == 0 || len(d.Input) >= len(bumpedSlit) || len(d.Input) <= 8 {
+		t.Errorf("disagreement not shrunk: %v", d)
+	}
+	if err := geometry.ValidatePolygon(d.Input, nil); err != nil {
+		t.Errorf("shrunk to an invalid polygon: %v", err)
+	}
+}
+
+func TestCutCornerKeepsPolygonsValid(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		points := randomPolygon(t, rng)
+		if geometry.ValidatePolygon(points, nil) != nil {
+			t.Fatalf("generated polygon %v is not simple", points)
+		}
+		candidates := cutCorner(points)
+		if len(points) > 4 && len(candidates) == 0 {
+			t.Fatalf("no smaller polygon for %v", points)
+		}
+		for _, c := range candidates {
+			if len(c) >= len(points) || geometry.ValidatePolygon(c, nil) != nil {
+				t.Fatalf("shrunk polygon %v is not simple and smaller", c)
+			}
+		}
+	}
+}
+
+// BenchmarkDay09Solutions races the registered solutions on generated
+// puzzle inputs. Only the staircase runs on the large ones; the others look
+// at every vertex pair.
+func BenchmarkDay09Solutions(b *testing.B) {
+	for _, vertices := range []int{496, 2000, 20000} {
+		redTiles, err := polygen.Generate(polygen.Options{Seed: 1, Shape: polygen.Notched, Vertices: vertices, MaxCoord: 100000})
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, impl := range rectangles.Implementations(day09Part2) {
+			b.Run(fmt.Sprintf("%s/%d", impl.Name, vertices), func(b *testing.B) {
+				if vertices > 2000 && impl.Name != "staircase" {
+					b.Skip("quadratic in the vertex count")
+				}
+				for i := 0; i < b.N; i++ {
+					impl.Solve(redTiles)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package bfs finds shortest move sequences over states encoded as small
+// integers, such as bitmasks of lights, by exhaustive breadth-first search.
+package bfs
+
+// Step returns the state reached by making move from state
+type Step func(state, move int) int
+
+// XOR is the step for toggle buttons: move i flips the bits of masks[i]
+func XOR(masks []int) Step {
+	return func(state, move int) int { return state ^ masks[move] }
+}
+
+// DenseBits is the widest state space that gets a visited bitset; wider
+// ones record visited states in a map instead
+const DenseBits = 26
+
+// Shortest searches from state 0 for target, trying moves 0 to moves-1 from
+// every state. States must fit in bits bits. It returns the moves of a
+// shortest path in order, or false when target cannot be reached; its
+// length is the number of moves.
+func Shortest(bits, moves, target int, step Step) ([]int, bool) {
+	seen := newVisited(bits)
+	seen.visit(0)
+
+	// Every reached state, in the order found, with how it was reached
+	type node struct {
+		state, parent, move int
+	}
+	nodes := []node{{state: 0, parent: -1}}
+	for head := 0; head < len(nodes); head++ {
+		cur := nodes[head]
+		if cur.state == target {
+			var path []int
+			for i := head; nodes[i].parent >= 0; i = nodes[i].parent {
+				path = append(path, nodes[i].move)
+			}
+			for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+				path[l], path[r] = path[r], path[l]
+			}
+			return path, true
+		}
+		for m := 0; m < moves; m++ {
+			next := step(cur.state, m)
+			if seen.visit(next) {
+				nodes = append(nodes, node{state: next, parent: head, move: m})
+			}
+		}
+	}
+	return nil, false
+}
+
+// visited marks states, reporting whether each was new
+type visited interface {
+	visit(state int) bool
+}
+
+func newVisited(bits int) visited {
+	if bits <= DenseBits {
+		return make(bitset, (1<<bits+63)/64)
+	}
+	return sparse{}
+}
+
+type bitset []uint64
+
+func (b bitset) visit(state int) bool {
+	w, bit := state/64, uint64(1)<<(state%64)
+	if b[w]&bit != 0 {
+		return false
+	}
+	b[w] |= bit
+	return true
+}
+
+type sparse map[int]struct{}
+
+func (s sparse) visit(state int) bool {
+	if _, ok := s[state]; ok {
+		return false
+	}
+	s[state] = struct{}{}
+	return true
+}
@@ -0,0 +1,72 @@
+package bfs
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShortestXOR(t *testing.T) {
+	// The day 10 example: buttons (3) (1,3) (2) (2,3) (0,2) (0,1), target
+	// [.##.] with light 0 as bit 0
+	masks := []int{0b1000, 0b1010, 0b0100, 0b1100, 0b0101, 0b0011}
+	path, ok := Shortest(4, len(masks), 0b0110, XOR(masks))
+	if !ok || len(path) != 2 {
+		t.Fatalf("got %v, %v; want two presses", path, ok)
+	}
+	state := 0
+	for _, m := range path {
+		state ^= masks[m]
+	}
+	if state != 0b0110 {
+		t.Errorf("path %v reaches %04b", path, state)
+	}
+}
+
+func TestShortest(t *testing.T) {
+	tests := []struct {
+		name   string
+		bits   int
+		target int
+		masks  []int
+		want   []int
+		ok     bool
+	}{
+		{"start is target", 3, 0, []int{1, 2}, nil, true},
+		{"one press", 3, 0b010, []int{0b001, 0b010}, []int{1}, true},
+		{"unreachable", 3, 0b100, []int{0b001, 0b010}, nil, false},
+		{"no buttons", 3, 0b001, nil, nil, false},
+		{"sparse states", 40, 1<<39 | 1, []int{1 << 39, 1, 1<<39 | 2}, []int{0, 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := Shortest(tt.bits, len(tt.masks), tt.target, XOR(tt.masks))
+			if ok != tt.ok || !slices.Equal(path, tt.want) {
+				t.Errorf("got %v, %v; want %v, %v", path, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestShortestCounter(t *testing.T) {
+	// Not every step is a toggle: +1 and *2 to reach 10 from 0 takes
+	// 0 1 2 4 5 10
+	step := func(state, move int) int {
+		if move == 0 {
+			return min(state+1, 31)
+		}
+		return min(state*2, 31)
+	}
+	path, ok := Shortest(5, 2, 10, step)
+	if !ok || !slices.Equal(path, []int{0, 0, 1, 0, 1}) {
+		t.Errorf("got %v, %v", path, ok)
+	}
+}
+
+func TestVisitedAgree(t *testing.T) {
+	dense, sparse := newVisited(8), newVisited(DenseBits+1)
+	for _, s := range []int{0, 5, 200, 5, 255, 0, 64, 63} {
+		if a, b := dense.visit(s), sparse.visit(s); a != b {
+			t.Errorf("state %d: dense %v, sparse %v", s, a, b)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package geometry
+
+import "sort"
+
+// CompressedGrid answers exactly whether every tile of a rectangle lies on
+// or inside a rectilinear polygon, like RectilinearPolygon.ContainsTiles
+// but in constant time. The x and y axes are compressed to the distinct vertex coordinates
+// plus the gaps between them: no edge starts or ends inside a gap, so each
+// compressed cell is either entirely valid or entirely invalid. A 2D prefix
+// sum over the invalid cells then answers any rectangle with four lookups.
+type CompressedGrid struct {
+	xs, ys []int   // sorted distinct vertex coordinates
+	bad    [][]int // prefix sums of invalid cells, padded by one row and column
+}
+
+// NewCompressedGrid classifies one representative tile per compressed cell
+// of the polygon with the given vertices: it is valid if it lies on the
+// boundary or inside the polygon. It takes memory quadratic in the number
+// of vertices.
+func NewCompressedGrid(vertices []Point) *CompressedGrid {
+	boundaryGrid := BoundaryGrid(vertices)
+	g := &CompressedGrid{
+		xs: distinct(vertices, func(p Point) int { return p.X }),
+		ys: distinct(vertices, func(p Point) int { return p.Y }),
+	}
+	cols := cellCount(g.xs)
+	rows := cellCount(g.ys)
+
+	type verticalEdge struct{ x, yMin, yMax int }
+	var edges []verticalEdge
+	for i := range vertices {
+		from := vertices[i]
+		to := vertices[(i+1)%len(vertices)]
+		if from.X == to.X && from.Y != to.Y {
+			edges = append(edges, verticalEdge{from.X, min(from.Y, to.Y), max(from.Y, to.Y)})
+		}
+	}
+
+	g.bad = make([][]int, rows+1)
+	g.bad[0] = make([]int, cols+1)
+	var crossings []int
+	for r := 0; r < rows; r++ {
+		g.bad[r+1] = make([]int, cols+1)
+		y, ok := representative(g.ys, r)
+
+		// Ray casting with a half-open rule: an edge counts when
+		// yMin <= y < yMax, and a tile is inside with an odd number of
+		// crossings to its right
+		crossings = crossings[:0]
+		for _, e := range edges {
+			if e.yMin <= y && y < e.yMax {
+				crossings = append(crossings, e.x)
+			}
+		}
+		sort.Ints(crossings)
+
+		left := 0 // crossings at or left of x
+		for c := 0; c < cols; c++ {
+			invalid := 0
+			if x, okX := representative(g.xs, c); ok && okX {
+				for left < len(crossings) && crossings[left] <= x {
+					left++
+				}
+				inside := (len(crossings)-left)%2 == 1
+				if !inside && !boundaryGrid.Get(x, y) {
+					invalid = 1
+				}
+			}
+			g.bad[r+1][c+1] = invalid + g.bad[r][c+1] + g.bad[r+1][c] - g.bad[r][c]
+		}
+	}
+	return g
+}
+
+// ContainsTiles is true when every tile of the rectangle with opposite
+// corners p1 and p2 lies on or inside the polygon
+func (g *CompressedGrid) ContainsTiles(p1, p2 Point) bool {
+	c1, ok1 := cellIndex(g.xs, min(p1.X, p2.X))
+	c2, ok2 := cellIndex(g.xs, max(p1.X, p2.X))
+	r1, ok3 := cellIndex(g.ys, min(p1.Y, p2.Y))
+	r2, ok4 := cellIndex(g.ys, max(p1.Y, p2.Y))
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return false
+	}
+	bad := g.bad[r2+1][c2+1] - g.bad[r1][c2+1] - g.bad[r2+1][c1] + g.bad[r1][c1]
+	return bad == 0
+}
+
+// distinct returns the sorted unique values of one coordinate
+func distinct(points []Point, coord func(Point) int) []int {
+	seen := make(map[int]bool, len(points))
+	var vals []int
+	for _, p := range points {
+		v := coord(p)
+		if !seen[v] {
+			seen[v] = true
+			vals = append(vals, v)
+		}
+	}
+	sort.Ints(vals)
+	return vals
+}
+
+// cellCount is the number of compressed cells: one per value and one per gap
+func cellCount(vals []int) int {
+	if len(vals) == 0 {
+		return 0
+	}
+	return 2*len(vals) - 1
+}
+
+// representative is a tile coordinate inside compressed cell i; false for an
+// empty gap between two adjacent values
+func representative(vals []int, i int) (int, bool) {
+	if i%2 == 0 {
+		return vals[i/2], true
+	}
+	v := vals[i/2] + 1
+	return v, v < vals[i/2+1]
+}
+
+// cellIndex maps a tile coordinate to its compressed cell: 2i for vals[i]
+// and 2i+1 for the gap after it; false when v lies outside all vertices
+func cellIndex(vals []int, v int) (int, bool) {
+	i := sort.SearchInts(vals, v)
+	if i < len(vals) && vals[i] == v {
+		return 2 * i, true
+	}
+	if i == 0 || i == len(vals) {
+		return 0, false
+	}
+	return 2*(i-1) + 1, true
+}
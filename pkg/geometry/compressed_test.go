@@ -0,0 +1,27 @@
+package geometry
+
+import "testing"
+
+func TestCompressedGridExample(t *testing.T) {
+	grid := NewCompressedGrid(exampleRedTiles)
+	tests := []struct {
+		name     string
+		p1, p2   Point
+		expected bool
+	}{
+		{"the area 24 answer", Point{9, 5}, Point{2, 3}, true},
+		{"right column", Point{9, 5}, Point{11, 7}, true},
+		{"area 50 crosses outside", Point{2, 5}, Point{11, 1}, false},
+		{"thin strip along the top edge", Point{7, 1}, Point{11, 1}, true},
+		{"outside every vertex", Point{0, 0}, Point{1, 1}, false},
+		{"single tile in a gap", Point{8, 2}, Point{8, 2}, true},
+		{"single tile outside in a gap", Point{4, 2}, Point{4, 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grid.ContainsTiles(tt.p1, tt.p2); got != tt.expected {
+				t.Errorf("ContainsTiles(%v, %v) = %v, expected %v", tt.p1, tt.p2, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package geometry holds the integer plane shared by the 2025 day 09
+// solutions: points, intervals and rectilinear polygons on a tile grid.
+package geometry
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"aoc/pkg/parse"
+)
+
+// Point represents a 2D coordinate
+type Point struct {
+	X, Y int
+}
+
+// Abs is the absolute value of x
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Rect is the rectangle of tiles with opposite corners A and B
+type Rect struct {
+	A, B Point
+}
+
+// Area counts the tiles of r, both corner rows and columns included
+func (r Rect) Area() int64 {
+	return int64(Abs(r.B.X-r.A.X)+1) * int64(Abs(r.B.Y-r.A.Y)+1)
+}
+
+// Bounds is the bounding box of points; all zero when there are none
+func Bounds(points []Point) (minX, maxX, minY, maxY int) {
+	if len(points) == 0 {
+		return 0, 0, 0, 0
+	}
+	minX, maxX = points[0].X, points[0].X
+	minY, maxY = points[0].Y, points[0].Y
+	for _, p := range points[1:] {
+		minX, maxX = min(minX, p.X), max(maxX, p.X)
+		minY, maxY = min(minY, p.Y), max(maxY, p.Y)
+	}
+	return minX, maxX, minY, maxY
+}
+
+// ParsePoints reads one "x,y" point per line; blank lines are skipped and
+// spaces around the numbers are allowed
+func ParsePoints(r io.Reader) ([]Point, error) {
+	points, _, err := parsePoints(r)
+	return points, err
+}
+
+// pointLine is one line of point input, before the spaces are trimmed
+var pointLine = parse.MustTemplate("point", "{x},{y}")
+
+// parsePoints is ParsePoints that also returns the input line of each point
+func parsePoints(r io.Reader) ([]Point, []int, error) {
+	var points []Point
+	var lines []int
+	err := parse.Lines(r, func(lineNo int, line string) error {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return nil
+		}
+		m, err := pointLine.Match(line)
+		if err != nil {
+			return fmt.Errorf("want x,y: %w", err)
+		}
+		x, err := strconv.Atoi(strings.TrimSpace(m.String("x")))
+		if err != nil {
+			return err
+		}
+		y, err := strconv.Atoi(strings.TrimSpace(m.String("y")))
+		if err != nil {
+			return err
+		}
+		points = append(points, Point{X: x, Y: y})
+		lines = append(lines, lineNo)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return points, lines, nil
+}
+
+// ReadPoints is ParsePoints on a file
+func ReadPoints(filename string) ([]Point, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	points, err := ParsePoints(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return points, nil
+}
@@ -0,0 +1,145 @@
+package geometry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAbs(t *testing.T) {
+	tests := []struct {
+		input    int
+		expected int
+	}{
+		{5, 5},
+		{-5, 5},
+		{0, 0},
+		{-1000000, 1000000},
+		{1000000, 1000000},
+	}
+
+	for _, tt := range tests {
+		result := Abs(tt.input)
+		if result != tt.expected {
+			t.Errorf("Abs(%d) = %d, expected %d", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		points  []Point
+		expMinX int
+		expMaxX int
+		expMinY int
+		expMaxY int
+	}{
+		{"Simple rectangle", []Point{{0, 0}, {5, 10}}, 0, 5, 0, 10},
+		{"Negative coordinates", []Point{{-5, -10}, {5, 10}}, -5, 5, -10, 10},
+		{"Single point", []Point{{3, 7}}, 3, 3, 7, 7},
+		{"No points", nil, 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minX, maxX, minY, maxY := Bounds(tt.points)
+			if minX != tt.expMinX || maxX != tt.expMaxX || minY != tt.expMinY || maxY != tt.expMaxY {
+				t.Errorf("Bounds: expected (%d,%d,%d,%d), got (%d,%d,%d,%d)",
+					tt.expMinX, tt.expMaxX, tt.expMinY, tt.expMaxY,
+					minX, maxX, minY, maxY)
+			}
+		})
+	}
+}
+
+// TestReadPoints tests the input parsing function
+func TestReadPoints(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test_input.txt")
+
+	content := `7,1
+11,1
+11,7
+9,7
+9,5
+2,5
+2,3
+7,3`
+
+	err := os.WriteFile(testFile, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	points, err := ReadPoints(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(points) != 8 {
+		t.Errorf("Expected 8 points, got %d", len(points))
+	}
+
+	if points[0].X != 7 || points[0].Y != 1 {
+		t.Errorf("Expected first point (7,1), got (%d,%d)", points[0].X, points[0].Y)
+	}
+
+	if points[7].X != 7 || points[7].Y != 3 {
+		t.Errorf("Expected last point (7,3), got (%d,%d)", points[7].X, points[7].Y)
+	}
+}
+
+// TestParsePointsWithWhitespace tests parsing with extra whitespace
+func TestParsePointsWithWhitespace(t *testing.T) {
+	content := `  7, 1  
+11 , 1
+
+9,5
+`
+	points, err := ParsePoints(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(points) != 3 {
+		t.Errorf("Expected 3 points, got %d", len(points))
+	}
+}
+
+func TestParsePointsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing comma", "7,1\n11 1\n", "line 2"},
+		{"not a number", "7,1\n\nx,3\n", "line 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePoints(strings.NewReader(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParsePoints() error = %v, want mention of %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRectArea(t *testing.T) {
+	tests := []struct {
+		r    Rect
+		want int64
+	}{
+		{Rect{Point{2, 5}, Point{11, 1}}, 50},
+		{Rect{Point{9, 5}, Point{2, 3}}, 24},
+		{Rect{Point{7, 1}, Point{7, 1}}, 1},
+		{Rect{Point{0, 0}, Point{99999, 99999}}, 10000000000},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Area(); got != tt.want {
+			t.Errorf("%v.Area() = %d, expected %d", tt.r, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package geometry
+
+import (
+	"slices"
+	"sort"
+
+	"aoc/pkg/interval"
+)
+
+// HorizontalSlab represents a horizontal strip with y in [YMin, YMax)
+type HorizontalSlab struct {
+	YMin, YMax int
+	// The tiles of the rows strictly inside the slab: x from one crossing
+	// edge to the next, both edges included
+	InsideXRanges interval.Ints
+}
+
+// Band is a run of rows YMin to YMax, both included, that all hold the
+// same tiles: a vertex row on its own, or the rows strictly between two
+// neighbouring vertex rows
+type Band struct {
+	YMin, YMax int
+	Tiles      *interval.Ints
+}
+
+// RectilinearPolygon is a simple closed polygon with axis-aligned edges,
+// given by its vertices in order. It is decomposed into horizontal slabs
+// between consecutive vertex rows, and into bands of rows with the same
+// tiles for containment queries.
+type RectilinearPolygon struct {
+	Vertices []Point
+	Slabs    []HorizontalSlab // sorted by YMin
+	YCoords  []int            // sorted unique Y coordinates
+	Bands    []Band           // every row from YCoords[0] down, in order
+}
+
+// NewRectilinearPolygon constructs the slab decomposition for efficient
+// containment queries
+func NewRectilinearPolygon(vertices []Point) *RectilinearPolygon {
+	n := len(vertices)
+	if n == 0 {
+		return &RectilinearPolygon{}
+	}
+
+	// Collect all unique Y coordinates
+	ySet := make(map[int]bool)
+	for _, v := range vertices {
+		ySet[v.Y] = true
+	}
+
+	yCoords := make([]int, 0, len(ySet))
+	for y := range ySet {
+		yCoords = append(yCoords, y)
+	}
+	sort.Ints(yCoords)
+
+	// Build vertical edges of the polygon
+	type verticalEdge struct {
+		X          int
+		YMin, YMax int
+	}
+
+	var verticalEdges []verticalEdge
+	for i := 0; i < n; i++ {
+		p1 := vertices[i]
+		p2 := vertices[(i+1)%n]
+
+		if p1.X == p2.X && p1.Y != p2.Y {
+			yMin, yMax := p1.Y, p2.Y
+			if yMin > yMax {
+				yMin, yMax = yMax, yMin
+			}
+			verticalEdges = append(verticalEdges, verticalEdge{X: p1.X, YMin: yMin, YMax: yMax})
+		}
+	}
+	sort.Slice(verticalEdges, func(i, j int) bool { return verticalEdges[i].YMin < verticalEdges[j].YMin })
+
+	// Build slabs with a scanline: no edge starts or ends strictly between
+	// two vertex rows, so the edges crossing slab i are those that start at
+	// or above row i and end below it. They are kept sorted by x as the
+	// scanline moves down.
+	slabs := make([]HorizontalSlab, 0, len(yCoords)-1)
+	var crossingX []int
+	ending := make(map[int][]int) // YMax to the x of the edges ending there
+	next := 0
+	for i := 0; i < len(yCoords)-1; i++ {
+		yMin := yCoords[i]
+		yMax := yCoords[i+1]
+
+		for _, x := range ending[yMin] {
+			j := sort.SearchInts(crossingX, x)
+			crossingX = slices.Delete(crossingX, j, j+1)
+		}
+		for ; next < len(verticalEdges) && verticalEdges[next].YMin == yMin; next++ {
+			e := verticalEdges[next]
+			j := sort.SearchInts(crossingX, e.X)
+			crossingX = slices.Insert(crossingX, j, e.X)
+			ending[e.YMax] = append(ending[e.YMax], e.X)
+		}
+
+		// Pair up crossings to form inside intervals (scanline fill)
+		// Going left-to-right, we toggle in/out at each crossing
+		slab := HorizontalSlab{YMin: yMin, YMax: yMax}
+		for j := 0; j+1 < len(crossingX); j += 2 {
+			slab.InsideXRanges.Insert(crossingX[j], crossingX[j+1])
+		}
+		slabs = append(slabs, slab)
+	}
+
+	// A vertex row holds the tiles of the slabs above and below it; the
+	// rows between two vertex rows share the tiles of their slab
+	bands := make([]Band, 0, 2*len(yCoords))
+	for i, y := range yCoords {
+		row := &interval.Ints{}
+		if i > 0 {
+			row = row.Union(&slabs[i-1].InsideXRanges)
+		}
+		if i < len(slabs) {
+			row = row.Union(&slabs[i].InsideXRanges)
+		}
+		bands = append(bands, Band{YMin: y, YMax: y, Tiles: row})
+		if i < len(slabs) && y+1 < slabs[i].YMax {
+			bands = append(bands, Band{YMin: y + 1, YMax: slabs[i].YMax - 1, Tiles: &slabs[i].InsideXRanges})
+		}
+	}
+
+	return &RectilinearPolygon{
+		Vertices: vertices,
+		Slabs:    slabs,
+		YCoords:  yCoords,
+		Bands:    bands,
+	}
+}
+
+// BandAt is the index in Bands of the band holding row y; false when the
+// polygon does not reach row y
+func (poly *RectilinearPolygon) BandAt(y int) (int, bool) {
+	i := sort.Search(len(poly.Bands), func(i int) bool { return poly.Bands[i].YMax >= y })
+	return i, i < len(poly.Bands) && poly.Bands[i].YMin <= y
+}
+
+// ContainsTiles checks if every whole tile of the rectangle with opposite
+// corners p1 and p2 lies in the polygon, boundary included. Only whole x
+// and y count, so a rectangle may cross a gap between two edges a tile
+// apart: both edges' tiles are in.
+func (poly *RectilinearPolygon) ContainsTiles(p1, p2 Point) bool {
+	x1, x2 := min(p1.X, p2.X), max(p1.X, p2.X)
+	y1, y2 := min(p1.Y, p2.Y), max(p1.Y, p2.Y)
+	i, ok := poly.BandAt(y1)
+	if !ok || y2 > poly.Bands[len(poly.Bands)-1].YMax {
+		return false
+	}
+
+	// The bands run on without gaps, so each one the rectangle reaches is
+	// checked once
+	for ; i < len(poly.Bands) && poly.Bands[i].YMin <= y2; i++ {
+		if !poly.Bands[i].Tiles.ContainsRange(x1, x2) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsPoint is true when p is inside the polygon or on its boundary.
+// A point strictly between two vertex rows lies in one slab; a point on a
+// vertex row is on the closed polygon when the slab on either side of the
+// row covers it.
+func (poly *RectilinearPolygon) ContainsPoint(p Point) bool {
+	i, ok := poly.BandAt(p.Y)
+	return ok && poly.Bands[i].Tiles.Contains(p.X)
+}
+
+// Area is the area enclosed by the boundary (shoelace formula), measured
+// between vertex centres rather than in whole tiles
+func (poly *RectilinearPolygon) Area() int64 {
+	twice := signedArea(poly.Vertices)
+	if twice < 0 {
+		twice = -twice
+	}
+	return twice / 2
+}
+
+// Perimeter is the total length of the edges
+func (poly *RectilinearPolygon) Perimeter() int64 {
+	n := len(poly.Vertices)
+	var total int64
+	for i := 0; i < n; i++ {
+		a, b := poly.Vertices[i], poly.Vertices[(i+1)%n]
+		total += int64(Abs(b.X-a.X) + Abs(b.Y-a.Y))
+	}
+	return total
+}
@@ -0,0 +1,266 @@
+package geometry
+
+import (
+	"slices"
+	"testing"
+)
+
+// Test data from the problem description
+var exampleRedTiles = []Point{
+	{7, 1},
+	{11, 1},
+	{11, 7},
+	{9, 7},
+	{9, 5},
+	{2, 5},
+	{2, 3},
+	{7, 3},
+}
+
+// TestNewRectilinearPolygon tests polygon construction
+func TestNewRectilinearPolygon(t *testing.T) {
+	polygon := NewRectilinearPolygon(exampleRedTiles)
+
+	if len(polygon.Vertices) != 8 {
+		t.Errorf("Expected 8 vertices, got %d", len(polygon.Vertices))
+	}
+
+	// Should have slabs between unique Y coordinates
+	// Y values: 1, 3, 5, 7 -> 3 slabs
+	if len(polygon.Slabs) != 3 {
+		t.Errorf("Expected 3 slabs, got %d", len(polygon.Slabs))
+	}
+
+	// Check Y coordinates are sorted
+	for i := 1; i < len(polygon.YCoords); i++ {
+		if polygon.YCoords[i] <= polygon.YCoords[i-1] {
+			t.Errorf("Y coordinates not sorted: %v", polygon.YCoords)
+			break
+		}
+	}
+}
+
+// TestNewRectilinearPolygonEmpty tests building polygon with no vertices
+func TestNewRectilinearPolygonEmpty(t *testing.T) {
+	polygon := NewRectilinearPolygon([]Point{})
+
+	if polygon == nil {
+		t.Fatal("Expected non-nil polygon even for empty input")
+	}
+
+	if len(polygon.Vertices) != 0 {
+		t.Errorf("Expected 0 vertices, got %d", len(polygon.Vertices))
+	}
+}
+
+// TestSlabStructure tests the internal slab structure
+func TestSlabStructure(t *testing.T) {
+	polygon := NewRectilinearPolygon(exampleRedTiles)
+
+	if len(polygon.Slabs) == 0 {
+		t.Fatal("No slabs created")
+	}
+
+	// First slab should start at minimum Y
+	minY := polygon.YCoords[0]
+	if polygon.Slabs[0].YMin != minY {
+		t.Errorf("First slab YMin = %d, expected %d", polygon.Slabs[0].YMin, minY)
+	}
+
+	// Last slab should end at maximum Y
+	maxY := polygon.YCoords[len(polygon.YCoords)-1]
+	lastSlab := polygon.Slabs[len(polygon.Slabs)-1]
+	if lastSlab.YMax != maxY {
+		t.Errorf("Last slab YMax = %d, expected %d", lastSlab.YMax, maxY)
+	}
+
+	// Slabs should be contiguous
+	for i := 1; i < len(polygon.Slabs); i++ {
+		if polygon.Slabs[i].YMin != polygon.Slabs[i-1].YMax {
+			t.Errorf("Gap between slabs %d and %d", i-1, i)
+		}
+	}
+
+	// Bands should cover every row from the first vertex row to the last,
+	// a vertex row on its own
+	if polygon.Bands[0].YMin != minY || polygon.Bands[len(polygon.Bands)-1].YMax != maxY {
+		t.Errorf("Bands run from %d to %d, expected %d to %d",
+			polygon.Bands[0].YMin, polygon.Bands[len(polygon.Bands)-1].YMax, minY, maxY)
+	}
+	for i, band := range polygon.Bands {
+		if i > 0 && band.YMin != polygon.Bands[i-1].YMax+1 {
+			t.Errorf("Gap between bands %d and %d", i-1, i)
+		}
+		_, vertexRow := slices.BinarySearch(polygon.YCoords, band.YMin)
+		if vertexRow && band.YMax != band.YMin {
+			t.Errorf("Band %d runs on from vertex row %d to %d", i, band.YMin, band.YMax)
+		}
+	}
+}
+
+// TestContainsTilesExample tests rectangle validation
+func TestContainsTilesExample(t *testing.T) {
+	polygon := NewRectilinearPolygon(exampleRedTiles)
+
+	tests := []struct {
+		name     string
+		p1, p2   Point
+		expected bool
+	}{
+		{"Valid rectangle (2,3) to (9,5) - the 24 area answer", Point{2, 3}, Point{9, 5}, true},
+		{"Valid rectangle (9,5) to (11,7)", Point{9, 5}, Point{11, 7}, true},
+		{"Invalid rectangle outside polygon - far left", Point{0, 0}, Point{1, 2}, false},
+		{"Invalid rectangle crossing outside boundary", Point{1, 1}, Point{5, 5}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := polygon.ContainsTiles(tt.p1, tt.p2)
+			if result != tt.expected {
+				t.Errorf("ContainsTiles(%v, %v) = %v, expected %v", tt.p1, tt.p2, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestContainsTilesFlat checks rectangles one tile tall or wide, which
+// lie on a single row or column of the polygon
+func TestContainsTilesFlat(t *testing.T) {
+	u := NewRectilinearPolygon([]Point{{0, 0}, {2, 0}, {2, 5}, {8, 5}, {8, 0}, {10, 0}, {10, 10}, {0, 10}})
+	tests := []struct {
+		name     string
+		p1, p2   Point
+		expected bool
+	}{
+		{"top row across the gap of the U", Point{0, 0}, Point{10, 0}, false},
+		{"top row past the polygon", Point{0, 0}, Point{100, 0}, false},
+		{"top of the left arm", Point{0, 0}, Point{2, 0}, true},
+		{"floor of the gap", Point{0, 5}, Point{10, 5}, true},
+		{"bottom row", Point{0, 10}, Point{10, 10}, true},
+		{"row between vertex rows", Point{0, 3}, Point{10, 3}, false},
+		{"column through the gap", Point{5, 0}, Point{5, 10}, false},
+		{"column below the gap", Point{5, 5}, Point{5, 10}, true},
+		{"above the polygon", Point{0, -3}, Point{2, 0}, false},
+		{"below the polygon", Point{0, 10}, Point{0, 12}, false},
+	}
+	for _, tt := range tests {
+		if got := u.ContainsTiles(tt.p1, tt.p2); got != tt.expected {
+			t.Errorf("%s: ContainsTiles(%v, %v) = %v, expected %v", tt.name, tt.p1, tt.p2, got, tt.expected)
+		}
+	}
+	if NewRectilinearPolygon(nil).ContainsTiles(Point{0, 0}, Point{0, 0}) {
+		t.Error("empty polygon contains a point")
+	}
+}
+
+// TestContainsTiles checks ContainsTiles against ContainsPoint tile by tile
+// on every rectangle around a few small polygons, one with a slit a tile
+// wide that rectangles may cross
+func TestContainsTiles(t *testing.T) {
+	slit := []Point{{0, 0}, {10, 0}, {10, 10}, {6, 10}, {6, 2}, {5, 2}, {5, 10}, {0, 10}}
+	if !NewRectilinearPolygon(slit).ContainsTiles(Point{0, 0}, Point{10, 10}) {
+		t.Error("slit square: tiles of the whole square not contained")
+	}
+
+	comb := []Point{{0, 0}, {1, 0}, {1, 3}, {2, 3}, {2, 0}, {4, 0}, {4, 1}, {5, 1}, {5, 4}, {0, 4}}
+	u := []Point{{0, 0}, {2, 0}, {2, 5}, {8, 5}, {8, 0}, {10, 0}, {10, 10}, {0, 10}}
+	for _, vertices := range [][]Point{slit, comb, u, exampleRedTiles} {
+		poly := NewRectilinearPolygon(vertices)
+		for y1 := -1; y1 <= 11; y1++ {
+			for y2 := y1; y2 <= 11; y2++ {
+				for x1 := -1; x1 <= 12; x1++ {
+					for x2 := x1; x2 <= 12; x2++ {
+						want := true
+						for y := y1; y <= y2 && want; y++ {
+							for x := x1; x <= x2 && want; x++ {
+								want = poly.ContainsPoint(Point{x, y})
+							}
+						}
+						if got := poly.ContainsTiles(Point{x1, y1}, Point{x2, y2}); got != want {
+							t.Fatalf("%v: ContainsTiles(%v, %v) = %v, expected %v",
+								vertices, Point{x1, y1}, Point{x2, y2}, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestContainsTilesNormalization tests that coordinate order doesn't matter
+func TestContainsTilesNormalization(t *testing.T) {
+	polygon := NewRectilinearPolygon(exampleRedTiles)
+
+	// These should all give the same result regardless of corner order
+	coords := []struct{ x1, y1, x2, y2 int }{
+		{2, 3, 9, 5},
+		{9, 3, 2, 5},
+		{2, 5, 9, 3},
+		{9, 5, 2, 3},
+	}
+
+	expected := polygon.ContainsTiles(Point{2, 3}, Point{9, 5})
+
+	for _, c := range coords {
+		result := polygon.ContainsTiles(Point{c.x1, c.y1}, Point{c.x2, c.y2})
+		if result != expected {
+			t.Errorf("ContainsTiles(%d,%d,%d,%d) = %v, expected %v (normalization issue)",
+				c.x1, c.y1, c.x2, c.y2, result, expected)
+		}
+	}
+}
+
+func TestContainsPoint(t *testing.T) {
+	square := []Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	tests := []struct {
+		name     string
+		polygon  []Point
+		point    Point
+		expected bool
+	}{
+		{"Point inside square", square, Point{2, 2}, true},
+		{"Point outside square", square, Point{5, 5}, false},
+		{"Point inside at (1, 1)", square, Point{1, 1}, true},
+		{"Point outside at (-1, -1)", square, Point{-1, -1}, false},
+		{"Corner", square, Point{4, 4}, true},
+		{"On bottom edge", square, Point{2, 0}, true},
+		{"Example: green boundary tile", exampleRedTiles, Point{5, 3}, true},
+		{"Example: inside the notch row", exampleRedTiles, Point{8, 4}, true},
+		{"Example: left of the notch", exampleRedTiles, Point{5, 2}, false},
+		{"Example: right of the right column", exampleRedTiles, Point{12, 6}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewRectilinearPolygon(tt.polygon).ContainsPoint(tt.point)
+			if result != tt.expected {
+				t.Errorf("ContainsPoint(%v): expected %v, got %v", tt.point, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAreaAndPerimeter(t *testing.T) {
+	tests := []struct {
+		name          string
+		polygon       []Point
+		wantArea      int64
+		wantPerimeter int64
+	}{
+		{"square", []Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}}, 16, 16},
+		{"square, other winding", []Point{{0, 0}, {0, 4}, {4, 4}, {4, 0}}, 16, 16},
+		{"example", exampleRedTiles, 30, 30},
+		{"empty", nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polygon := NewRectilinearPolygon(tt.polygon)
+			if got := polygon.Area(); got != tt.wantArea {
+				t.Errorf("Area() = %d, want %d", got, tt.wantArea)
+			}
+			if got := polygon.Perimeter(); got != tt.wantPerimeter {
+				t.Errorf("Perimeter() = %d, want %d", got, tt.wantPerimeter)
+			}
+		})
+	}
+}
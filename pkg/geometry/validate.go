@@ -0,0 +1,312 @@
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PolygonError is one problem with a vertex loop, pinned to the input lines
+// of the vertices involved
+type PolygonError struct {
+	Lines   []int
+	Problem string
+}
+
+func (e *PolygonError) Error() string {
+	if len(e.Lines) == 0 {
+		return e.Problem
+	}
+	nums := make([]string, len(e.Lines))
+	for i, l := range e.Lines {
+		nums[i] = strconv.Itoa(l)
+	}
+	word := "line"
+	if len(nums) > 1 {
+		word = "lines"
+	}
+	return fmt.Sprintf("%s %s: %s", word, strings.Join(nums, ", "), e.Problem)
+}
+
+// ValidatePolygon checks that vertices form what the puzzle promises: a
+// closed loop of alternating horizontal and vertical edges that never
+// touches itself. Either winding is accepted. lines[i] is the input line of
+// vertices[i]; when lines is nil the vertices are numbered from 1. Every
+// problem found is reported, joined into one error.
+func ValidatePolygon(vertices []Point, lines []int) error {
+	if lines == nil {
+		lines = make([]int, len(vertices))
+		for i := range lines {
+			lines[i] = i + 1
+		}
+	}
+	n := len(vertices)
+	if n < 4 {
+		return &PolygonError{Problem: fmt.Sprintf("a polygon needs at least 4 vertices, got %d", n)}
+	}
+
+	var errs []error
+	report := func(problem string, idx ...int) {
+		ls := make([]int, len(idx))
+		for i, j := range idx {
+			ls[i] = lines[j]
+		}
+		errs = append(errs, &PolygonError{Lines: ls, Problem: problem})
+	}
+
+	seen := make(map[Point]int, n)
+	for i, v := range vertices {
+		if j, ok := seen[v]; ok {
+			report(fmt.Sprintf("duplicate vertex %d,%d", v.X, v.Y), j, i)
+			continue
+		}
+		seen[v] = i
+	}
+
+	// Edge i runs from vertex i to vertex i+1
+	straight := make([]bool, n)
+	for i := range vertices {
+		a, b := vertices[i], vertices[(i+1)%n]
+		straight[i] = a != b && (a.X == b.X || a.Y == b.Y)
+		if a != b && !straight[i] {
+			report(fmt.Sprintf("diagonal edge %d,%d -> %d,%d", a.X, a.Y, b.X, b.Y), i, (i+1)%n)
+		}
+	}
+
+	for i, v := range vertices {
+		prev, next := vertices[(i+n-1)%n], vertices[(i+1)%n]
+		if !straight[(i+n-1)%n] || !straight[i] {
+			continue
+		}
+		if prev.X == v.X && v.X == next.X || prev.Y == v.Y && v.Y == next.Y {
+			report(fmt.Sprintf("vertex %d,%d is not a corner: both its edges run the same way", v.X, v.Y), i)
+		}
+	}
+
+	for _, pair := range touchingEdges(vertices, straight) {
+		i, j := pair[0], pair[1]
+		report("edges cross or touch", i, (i+1)%n, j, (j+1)%n)
+	}
+	return errors.Join(errs...)
+}
+
+// ParsePolygon is ParsePoints followed by ValidatePolygon, with problems
+// reported against the input lines. The vertices come back in input order;
+// clockwise tells whether they run clockwise on screen (y grows downwards),
+// as the puzzle lists them, for callers that care about the winding.
+func ParsePolygon(r io.Reader) (points []Point, clockwise bool, err error) {
+	points, lines, err := parsePoints(r)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := ValidatePolygon(points, lines); err != nil {
+		return nil, false, err
+	}
+	return points, Clockwise(points), nil
+}
+
+// ReadPolygon is ParsePolygon on a file
+func ReadPolygon(filename string) (points []Point, clockwise bool, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+	points, clockwise, err = ParsePolygon(file)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", filename, err)
+	}
+	return points, clockwise, nil
+}
+
+// touchingEdges returns the pairs i < j of straight edges, not next to each
+// other, that share a point, in order. Edge i runs from vertex i to vertex
+// i+1.
+//
+// A sweep from left to right counts, for every vertical edge, the
+// horizontal edges it meets. In a valid polygon that is just its two
+// neighbours, so only vertical edges meeting more are checked pair by pair.
+// Collinear edges meet when they overlap, which sorting each row and column
+// finds. A valid polygon costs O(n log n).
+func touchingEdges(vertices []Point, straight []bool) [][2]int {
+	n := len(vertices)
+	var horizontal, vertical []axisEdge
+	for i, ok := range straight {
+		if !ok {
+			continue
+		}
+		a, b := vertices[i], vertices[(i+1)%n]
+		if a.Y == b.Y {
+			horizontal = append(horizontal, axisEdge{i, a.Y, min(a.X, b.X), max(a.X, b.X)})
+		} else {
+			vertical = append(vertical, axisEdge{i, a.X, min(a.Y, b.Y), max(a.Y, b.Y)})
+		}
+	}
+
+	var pairs [][2]int
+	add := func(i, j int) {
+		if d := (j - i + n) % n; d != 1 && d != n-1 {
+			pairs = append(pairs, [2]int{min(i, j), max(i, j)})
+		}
+	}
+	for _, v := range crowdedVerticals(horizontal, vertical, straight, vertices) {
+		for _, h := range horizontal {
+			if h.Lo <= v.At && v.At <= h.Hi && v.Lo <= h.At && h.At <= v.Hi {
+				add(h.Index, v.Index)
+			}
+		}
+	}
+	for _, edges := range [][]axisEdge{horizontal, vertical} {
+		overlappingEdges(edges, add)
+	}
+
+	slices.SortFunc(pairs, func(a, b [2]int) int {
+		if a[0] != b[0] {
+			return a[0] - b[0]
+		}
+		return a[1] - b[1]
+	})
+	return slices.Compact(pairs)
+}
+
+// axisEdge is edge Index lying at At across [Lo, Hi]: at y = At from x = Lo
+// to Hi when horizontal, at x = At from y = Lo to Hi when vertical
+type axisEdge struct {
+	Index, At, Lo, Hi int
+}
+
+// crowdedVerticals returns the vertical edges that meet a horizontal edge
+// other than their neighbours
+func crowdedVerticals(horizontal, vertical []axisEdge, straight []bool, vertices []Point) []axisEdge {
+	n := len(vertices)
+	ys := make([]int, len(horizontal))
+	for i, h := range horizontal {
+		ys[i] = h.At
+	}
+	slices.Sort(ys)
+	ys = slices.Compact(ys)
+
+	// A horizontal edge is in the tree of rows from its left end to its
+	// right end; both ends count, so at one x it goes in before the
+	// vertical edges look and comes out after
+	const (
+		enter = iota
+		look
+		leave
+	)
+	type event struct {
+		x, kind int
+		edge    axisEdge
+	}
+	events := make([]event, 0, 2*len(horizontal)+len(vertical))
+	for _, h := range horizontal {
+		events = append(events, event{h.Lo, enter, h}, event{h.Hi, leave, h})
+	}
+	for _, v := range vertical {
+		events = append(events, event{v.At, look, v})
+	}
+	slices.SortFunc(events, func(a, b event) int {
+		if a.x != b.x {
+			return a.x - b.x
+		}
+		return a.kind - b.kind
+	})
+
+	rows := make(fenwick, len(ys)+1)
+	var crowded []axisEdge
+	for _, e := range events {
+		switch e.kind {
+		case enter, leave:
+			row, _ := slices.BinarySearch(ys, e.edge.At)
+			rows.add(row, 1-e.kind) // +1 on enter, -1 on leave
+		case look:
+			lo, _ := slices.BinarySearch(ys, e.edge.Lo)
+			hi, found := slices.BinarySearch(ys, e.edge.Hi)
+			if found {
+				hi++
+			}
+			neighbours := 0
+			for _, k := range []int{(e.edge.Index + n - 1) % n, (e.edge.Index + 1) % n} {
+				a, b := vertices[k], vertices[(k+1)%n]
+				if straight[k] && a.Y == b.Y {
+					neighbours++
+				}
+			}
+			if rows.sum(hi)-rows.sum(lo) > neighbours {
+				crowded = append(crowded, e.edge)
+			}
+		}
+	}
+	return crowded
+}
+
+// overlappingEdges calls add for every two collinear edges that share a
+// point. Edges are sorted along each line, and only an edge starting before
+// the furthest end so far is compared with those before it.
+func overlappingEdges(edges []axisEdge, add func(i, j int)) {
+	edges = slices.Clone(edges)
+	slices.SortFunc(edges, func(a, b axisEdge) int {
+		if a.At != b.At {
+			return a.At - b.At
+		}
+		return a.Lo - b.Lo
+	})
+	for start := 0; start < len(edges); {
+		end := start + 1
+		for end < len(edges) && edges[end].At == edges[start].At {
+			end++
+		}
+		furthest := edges[start].Hi
+		for k := start + 1; k < end; k++ {
+			if edges[k].Lo <= furthest {
+				for _, e := range edges[start:k] {
+					if edges[k].Lo <= e.Hi {
+						add(e.Index, edges[k].Index)
+					}
+				}
+			}
+			furthest = max(furthest, edges[k].Hi)
+		}
+		start = end
+	}
+}
+
+// fenwick is a binary indexed tree of counts
+type fenwick []int
+
+// add adds delta at position i, from 0
+func (f fenwick) add(i, delta int) {
+	for i++; i < len(f); i += i & -i {
+		f[i] += delta
+	}
+}
+
+// sum is the total below position i
+func (f fenwick) sum(i int) int {
+	total := 0
+	for ; i > 0; i -= i & -i {
+		total += f[i]
+	}
+	return total
+}
+
+// Clockwise reports whether vertices run clockwise on screen (y grows
+// downwards)
+func Clockwise(vertices []Point) bool {
+	return signedArea(vertices) > 0
+}
+
+// signedArea is twice the shoelace area: positive when the vertices run
+// clockwise on screen
+func signedArea(vertices []Point) int64 {
+	var sum int64
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		sum += int64(a.X)*int64(b.Y) - int64(b.X)*int64(a.Y)
+	}
+	return sum
+}
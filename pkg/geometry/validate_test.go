@@ -0,0 +1,197 @@
+package geometry
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidatePolygon(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		errors []string // each must appear in the error, none when empty
+	}{
+		{
+			name:  "puzzle example",
+			input: "7,1\n11,1\n11,7\n9,7\n9,5\n2,5\n2,3\n7,3\n",
+		},
+		{
+			name:   "diagonal edge",
+			input:  "0,0\n4,0\n4,4\n2,6\n0,4\n",
+			errors: []string{"lines 3, 4: diagonal edge 4,4 -> 2,6", "lines 4, 5: diagonal edge 2,6 -> 0,4"},
+		},
+		{
+			name:   "duplicate vertex",
+			input:  "0,0\n4,0\n4,4\n0,4\n\n4,0\n",
+			errors: []string{"lines 2, 6: duplicate vertex 4,0"},
+		},
+		{
+			name:   "collinear vertex",
+			input:  "0,0\n2,0\n4,0\n4,4\n0,4\n",
+			errors: []string{"line 2: vertex 2,0 is not a corner"},
+		},
+		{
+			name:   "self intersection",
+			input:  "0,0\n6,0\n6,2\n2,2\n2,-2\n4,-2\n4,4\n0,4\n",
+			errors: []string{"lines 1, 2, 4, 5: edges cross or touch", "lines 1, 2, 6, 7: edges cross or touch", "lines 3, 4, 6, 7: edges cross or touch"},
+		},
+		{
+			name:  "counter-clockwise",
+			input: "0,0\n0,4\n4,4\n4,0\n",
+		},
+		{
+			name:   "too few vertices",
+			input:  "0,0\n4,0\n4,4\n",
+			errors: []string{"at least 4 vertices, got 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParsePolygon(strings.NewReader(tt.input))
+			if len(tt.errors) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %q, got none", tt.errors)
+			}
+			for _, want := range tt.errors {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+			var pe *PolygonError
+			if !errors.As(err, &pe) {
+				t.Errorf("error %v is not a *PolygonError", err)
+			}
+		})
+	}
+}
+
+func TestValidatePolygonNumbersVertices(t *testing.T) {
+	err := ValidatePolygon([]Point{{0, 0}, {4, 0}, {4, 4}, {1, 3}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "lines 3, 4: diagonal edge") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestReadPolygon(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(filename, []byte("0,0\n4,0\n4,4\n0,4\n0,0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := ReadPolygon(filename)
+	if err == nil || !strings.HasPrefix(err.Error(), filename+": lines 1, 5:") {
+		t.Errorf("got %v", err)
+	}
+
+	real, clockwise, err := ReadPolygon("../../2025/day09/input.txt")
+	if err != nil {
+		t.Fatalf("puzzle input rejected: %v", err)
+	}
+	if len(real) != 496 || !clockwise {
+		t.Errorf("got %d vertices, clockwise %v", len(real), clockwise)
+	}
+}
+
+func TestParsePolygonWinding(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      []Point
+		clockwise bool
+	}{
+		{"0,0\n4,0\n4,4\n0,4\n", []Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}}, true},
+		{"0,0\n0,4\n4,4\n4,0\n", []Point{{0, 0}, {0, 4}, {4, 4}, {4, 0}}, false},
+	}
+	for _, tt := range tests {
+		got, clockwise, err := ParsePolygon(strings.NewReader(tt.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, tt.want) || clockwise != tt.clockwise {
+			t.Errorf("%q: got %v, clockwise %v, want %v, clockwise %v", tt.input, got, clockwise, tt.want, tt.clockwise)
+		}
+	}
+}
+
+// segmentsTouch reports whether two axis-aligned segments share a point
+func segmentsTouch(a1, a2, b1, b2 Point) bool {
+	return max(min(a1.X, a2.X), min(b1.X, b2.X)) <= min(max(a1.X, a2.X), max(b1.X, b2.X)) &&
+		max(min(a1.Y, a2.Y), min(b1.Y, b2.Y)) <= min(max(a1.Y, a2.Y), max(b1.Y, b2.Y))
+}
+
+// TestTouchingEdgesMatchesPairs checks the sweep against every pair of
+// edges on random loops, most of which cross themselves
+func TestTouchingEdgesMatchesPairs(t *testing.T) {
+	rng := rand.New(rand.NewSource(35))
+	for round := 0; round < 2000; round++ {
+		// Alternate horizontal and vertical steps on a small grid, with a
+		// diagonal or a repeated point now and then
+		n := 4 + rng.Intn(12)
+		vertices := make([]Point, n)
+		for i := range vertices {
+			if i == 0 || rng.Intn(10) == 0 {
+				vertices[i] = Point{rng.Intn(6), rng.Intn(6)}
+				continue
+			}
+			vertices[i] = vertices[i-1]
+			if i%2 == 1 {
+				vertices[i].X = rng.Intn(6)
+			} else {
+				vertices[i].Y = rng.Intn(6)
+			}
+		}
+		straight := make([]bool, n)
+		for i, a := range vertices {
+			b := vertices[(i+1)%n]
+			straight[i] = a != b && (a.X == b.X || a.Y == b.Y)
+		}
+
+		var want [][2]int
+		for i := 0; i < n; i++ {
+			for j := i + 2; j < n; j++ {
+				if i == 0 && j == n-1 || !straight[i] || !straight[j] {
+					continue
+				}
+				if segmentsTouch(vertices[i], vertices[(i+1)%n], vertices[j], vertices[(j+1)%n]) {
+					want = append(want, [2]int{i, j})
+				}
+			}
+		}
+		if got := touchingEdges(vertices, straight); !slices.Equal(got, want) {
+			t.Fatalf("%v: got %v, want %v", vertices, got, want)
+		}
+	}
+}
+
+// bigStaircase is a valid polygon of n vertices: a staircase down to the
+// right, closed along the bottom and left
+func bigStaircase(n int) []Point {
+	var vertices []Point
+	steps := (n - 2) / 2
+	for i := 0; i < steps; i++ {
+		vertices = append(vertices, Point{2 * i, 2 * i}, Point{2*i + 2, 2 * i})
+	}
+	return append(vertices, Point{2 * steps, 2 * steps}, Point{0, 2 * steps})
+}
+
+func TestValidatePolygonLarge(t *testing.T) {
+	if err := ValidatePolygon(bigStaircase(50000), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkValidatePolygon(b *testing.B) {
+	vertices := bigStaircase(50000)
+	for i := 0; i < b.N; i++ {
+		ValidatePolygon(vertices, nil)
+	}
+}
@@ -0,0 +1,105 @@
+package geometry
+
+import (
+	"slices"
+	"sort"
+)
+
+// ValidityGridBuilder collects the marked tiles of a ValidityGrid. It is
+// meant for one goroutine; Freeze turns it into a grid any number of
+// goroutines can read without locking.
+type ValidityGridBuilder struct {
+	rows map[int][]tileSpan
+}
+
+// tileSpan marks the tiles Lo to Hi of a row, both included
+type tileSpan struct {
+	Lo, Hi int
+}
+
+// NewValidityGridBuilder returns a builder with no tiles marked
+func NewValidityGridBuilder() *ValidityGridBuilder {
+	return &ValidityGridBuilder{rows: make(map[int][]tileSpan)}
+}
+
+// Set marks the tile at x, y
+func (b *ValidityGridBuilder) Set(x, y int) {
+	b.SetRow(y, x, x)
+}
+
+// SetRow marks the tiles x1 to x2 of row y, both included, in one go
+func (b *ValidityGridBuilder) SetRow(y, x1, x2 int) {
+	b.rows[y] = append(b.rows[y], tileSpan{min(x1, x2), max(x1, x2)})
+}
+
+// Freeze sorts and merges every row into the grid. The builder can keep
+// being used; later marks do not show up in grids already frozen.
+func (b *ValidityGridBuilder) Freeze() *ValidityGrid {
+	g := &ValidityGrid{}
+	if len(b.rows) == 0 {
+		return g
+	}
+	g.minY, g.maxY = int(^uint(0)>>1), -int(^uint(0)>>1)-1
+	for y := range b.rows {
+		g.minY, g.maxY = min(g.minY, y), max(g.maxY, y)
+	}
+	g.rows = make([][]tileSpan, g.maxY-g.minY+1)
+	for y, spans := range b.rows {
+		spans = slices.Clone(spans)
+		slices.SortFunc(spans, func(a, b tileSpan) int { return a.Lo - b.Lo })
+		merged := spans[:1]
+		for _, s := range spans[1:] {
+			last := &merged[len(merged)-1]
+			if s.Lo <= last.Hi+1 {
+				last.Hi = max(last.Hi, s.Hi)
+			} else {
+				merged = append(merged, s)
+			}
+		}
+		g.rows[y-g.minY] = slices.Clip(merged)
+	}
+	return g
+}
+
+// ValidityGrid answers whether a tile was marked. Each row is a sorted list
+// of disjoint spans, so the boundary of a polygon with long edges takes
+// little memory, and a lookup is a binary search within one row. It never
+// changes after Freeze, so reads need no lock.
+type ValidityGrid struct {
+	minY, maxY int
+	rows       [][]tileSpan // row y lives at y-minY
+}
+
+// Get reports whether the tile at x, y is marked
+func (g *ValidityGrid) Get(x, y int) bool {
+	if y < g.minY || y > g.maxY || g.rows == nil {
+		return false
+	}
+	row := g.rows[y-g.minY]
+	i := sort.Search(len(row), func(i int) bool { return row[i].Hi >= x })
+	return i < len(row) && row[i].Lo <= x
+}
+
+// BoundaryGrid marks the tiles on the boundary of the polygon with the
+// given vertices, without filling the inside. Edges that are not
+// axis-aligned are skipped; ValidatePolygon rejects them. Horizontal edges
+// go in as whole spans.
+func BoundaryGrid(vertices []Point) *ValidityGrid {
+	builder := NewValidityGridBuilder()
+	for _, p := range vertices {
+		builder.Set(p.X, p.Y)
+	}
+	for i := range vertices {
+		from := vertices[i]
+		to := vertices[(i+1)%len(vertices)]
+		if from.X == to.X {
+			// Vertical edge: one tile per row
+			for y := min(from.Y, to.Y); y <= max(from.Y, to.Y); y++ {
+				builder.Set(from.X, y)
+			}
+		} else if from.Y == to.Y {
+			builder.SetRow(from.Y, from.X, to.X)
+		}
+	}
+	return builder.Freeze()
+}
@@ -0,0 +1,40 @@
+package geometry
+
+import "testing"
+
+func TestValidityGrid(t *testing.T) {
+	b := NewValidityGridBuilder()
+	b.SetRow(3, 10, 5)
+	b.SetRow(3, 11, 12) // touches 5-10, merges into 5-12
+	b.SetRow(3, 20, 25)
+	b.SetRow(3, 22, 23) // inside 20-25
+	b.Set(-4, -2)
+	g := b.Freeze()
+
+	tests := []struct {
+		x, y int
+		want bool
+	}{
+		{5, 3, true}, {12, 3, true}, {13, 3, false}, {4, 3, false},
+		{19, 3, false}, {20, 3, true}, {25, 3, true}, {26, 3, false},
+		{-4, -2, true}, {-3, -2, false},
+		{5, 0, false}, {5, 4, false}, {5, -3, false},
+	}
+	for _, tt := range tests {
+		if got := g.Get(tt.x, tt.y); got != tt.want {
+			t.Errorf("Get(%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+	if got := len(g.rows[3-g.minY]); got != 2 {
+		t.Errorf("row 3 holds %d spans, want 2", got)
+	}
+
+	// Frozen grids do not see later marks
+	b.Set(0, 0)
+	if g.Get(0, 0) || !b.Freeze().Get(0, 0) {
+		t.Error("freeze is not a snapshot")
+	}
+	if NewValidityGridBuilder().Freeze().Get(0, 0) {
+		t.Error("empty grid has a marked tile")
+	}
+}
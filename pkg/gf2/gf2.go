@@ -0,0 +1,272 @@
+// Package gf2 solves linear systems over GF(2), where adding is XOR. Rows
+// and vectors are multiword bitsets, so systems are not limited to 64
+// variables or equations.
+package gf2
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Vector is a bitset; bit i lives in word i/64
+type Vector []uint64
+
+// NewVector returns an all-zero vector with room for n bits
+func NewVector(n int) Vector {
+	return make(Vector, (n+63)/64)
+}
+
+// Bit reports whether bit i is set
+func (v Vector) Bit(i int) bool {
+	return v[i/64]&(1<<(i%64)) != 0
+}
+
+// Set sets bit i
+func (v Vector) Set(i int) {
+	v[i/64] |= 1 << (i % 64)
+}
+
+// Flip toggles bit i
+func (v Vector) Flip(i int) {
+	v[i/64] ^= 1 << (i % 64)
+}
+
+// Xor adds w into v; w must be no longer than v
+func (v Vector) Xor(w Vector) {
+	for i, word := range w {
+		v[i] ^= word
+	}
+}
+
+// OnesCount is the Hamming weight of v
+func (v Vector) OnesCount() int {
+	n := 0
+	for _, word := range v {
+		n += bits.OnesCount64(word)
+	}
+	return n
+}
+
+// IsZero reports whether no bit is set
+func (v Vector) IsZero() bool {
+	for _, word := range v {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Ones lists the set bits in increasing order
+func (v Vector) Ones() []int {
+	var ones []int
+	for w, word := range v {
+		for word != 0 {
+			ones = append(ones, w*64+bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+	return ones
+}
+
+// Clone returns a copy of v
+func (v Vector) Clone() Vector {
+	return append(Vector(nil), v...)
+}
+
+// Format writes the first n bits, bit 0 first
+func (v Vector) Format(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		if v.Bit(i) {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+// Matrix is Rows equations in Cols unknowns
+type Matrix struct {
+	Rows, Cols int
+	rows       []Vector
+}
+
+// NewMatrix returns an all-zero rows x cols matrix
+func NewMatrix(rows, cols int) *Matrix {
+	m := &Matrix{Rows: rows, Cols: cols, rows: make([]Vector, rows)}
+	for r := range m.rows {
+		m.rows[r] = NewVector(cols)
+	}
+	return m
+}
+
+// Bit reports whether entry (r, c) is 1
+func (m *Matrix) Bit(r, c int) bool {
+	return m.rows[r].Bit(c)
+}
+
+// Set sets entry (r, c) to 1
+func (m *Matrix) Set(r, c int) {
+	m.rows[r].Set(c)
+}
+
+// Row is row r, shared with the matrix
+func (m *Matrix) Row(r int) Vector {
+	return m.rows[r]
+}
+
+// Apply returns m x
+func (m *Matrix) Apply(x Vector) Vector {
+	out := NewVector(m.Rows)
+	for r, row := range m.rows {
+		parity := 0
+		for w, word := range row {
+			parity ^= bits.OnesCount64(word & x[w])
+		}
+		if parity&1 == 1 {
+			out.Set(r)
+		}
+	}
+	return out
+}
+
+// echelon is a matrix in reduced row echelon form together with its
+// right-hand side. Row i of the first len(pivots) has its leading 1 in
+// column pivots[i] and no other row has a 1 there.
+type echelon struct {
+	cols   int
+	rows   []Vector
+	rhs    []bool
+	pivots []int
+}
+
+// eliminate runs Gauss-Jordan elimination on a copy of m with b on the
+// right; a nil b is all zeros
+func (m *Matrix) eliminate(b Vector) echelon {
+	e := echelon{cols: m.Cols, rows: make([]Vector, m.Rows), rhs: make([]bool, m.Rows)}
+	for r, row := range m.rows {
+		e.rows[r] = row.Clone()
+		e.rhs[r] = b != nil && b.Bit(r)
+	}
+	rank := 0
+	for c := 0; c < m.Cols && rank < m.Rows; c++ {
+		pivot := -1
+		for r := rank; r < m.Rows; r++ {
+			if e.rows[r].Bit(c) {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		e.rows[rank], e.rows[pivot] = e.rows[pivot], e.rows[rank]
+		e.rhs[rank], e.rhs[pivot] = e.rhs[pivot], e.rhs[rank]
+		for r := range e.rows {
+			if r != rank && e.rows[r].Bit(c) {
+				e.rows[r].Xor(e.rows[rank])
+				e.rhs[r] = e.rhs[r] != e.rhs[rank]
+			}
+		}
+		e.pivots = append(e.pivots, c)
+		rank++
+	}
+	return e
+}
+
+// consistent reports whether the zero rows left over have zero on the right
+func (e echelon) consistent() bool {
+	for r := len(e.pivots); r < len(e.rows); r++ {
+		if e.rhs[r] {
+			return false
+		}
+	}
+	return true
+}
+
+// particular is the solution with every free variable zero
+func (e echelon) particular() Vector {
+	x := NewVector(e.cols)
+	for i, c := range e.pivots {
+		if e.rhs[i] {
+			x.Set(c)
+		}
+	}
+	return x
+}
+
+// nullSpace has one basis vector per free column: that column set and the
+// pivot columns that cancel it
+func (e echelon) nullSpace() []Vector {
+	isPivot := make([]bool, e.cols)
+	for _, c := range e.pivots {
+		isPivot[c] = true
+	}
+	var basis []Vector
+	for f := 0; f < e.cols; f++ {
+		if isPivot[f] {
+			continue
+		}
+		v := NewVector(e.cols)
+		v.Set(f)
+		for i, c := range e.pivots {
+			if e.rows[i].Bit(f) {
+				v.Set(c)
+			}
+		}
+		basis = append(basis, v)
+	}
+	return basis
+}
+
+// Rank is the number of linearly independent rows
+func (m *Matrix) Rank() int {
+	return len(m.eliminate(nil).pivots)
+}
+
+// NullSpace returns a basis of the solutions to m x = 0, one vector per
+// free variable
+func (m *Matrix) NullSpace() []Vector {
+	return m.eliminate(nil).nullSpace()
+}
+
+// ErrNoSolution is returned when m x = b has no solution
+var ErrNoSolution = errors.New("gf2: no solution")
+
+// Solve returns one solution of m x = b and a basis of the null space;
+// every solution is x plus a sum of basis vectors
+func (m *Matrix) Solve(b Vector) (Vector, []Vector, error) {
+	e := m.eliminate(b)
+	if !e.consistent() {
+		return nil, nil, ErrNoSolution
+	}
+	return e.particular(), e.nullSpace(), nil
+}
+
+// MaxFree caps the free variables MinWeightSolution will enumerate
+const MaxFree = 32
+
+// MinWeightSolution returns a solution of m x = b with the fewest ones. It
+// tries all 2^k combinations of the k null-space vectors in Gray code
+// order, one XOR per step, so k is limited to MaxFree.
+func (m *Matrix) MinWeightSolution(b Vector) (Vector, error) {
+	x, basis, err := m.Solve(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(basis) > MaxFree {
+		return nil, fmt.Errorf("gf2: %d free variables, at most %d are enumerated", len(basis), MaxFree)
+	}
+	best, bestWeight := x.Clone(), x.OnesCount()
+	for i := uint64(1); i < 1<<len(basis); i++ {
+		x.Xor(basis[bits.TrailingZeros64(i)])
+		if w := x.OnesCount(); w < bestWeight {
+			best, bestWeight = x.Clone(), w
+		}
+	}
+	return best, nil
+}
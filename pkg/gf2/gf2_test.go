@@ -0,0 +1,201 @@
+package gf2
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// matrixOf builds a matrix from rows written as "0110"
+func matrixOf(rows ...string) *Matrix {
+	m := NewMatrix(len(rows), len(rows[0]))
+	for r, row := range rows {
+		for c, ch := range row {
+			if ch == '1' {
+				m.Set(r, c)
+			}
+		}
+	}
+	return m
+}
+
+func vectorOf(s string) Vector {
+	v := NewVector(len(s))
+	for i, ch := range s {
+		if ch == '1' {
+			v.Set(i)
+		}
+	}
+	return v
+}
+
+func TestVector(t *testing.T) {
+	v := NewVector(130)
+	if len(v) != 3 {
+		t.Fatalf("130 bits in %d words", len(v))
+	}
+	v.Set(0)
+	v.Set(64)
+	v.Set(129)
+	v.Flip(64)
+	v.Flip(70)
+	if got := v.Ones(); !slices.Equal(got, []int{0, 70, 129}) {
+		t.Errorf("Ones() = %v", got)
+	}
+	if v.OnesCount() != 3 || v.IsZero() {
+		t.Errorf("OnesCount() = %d, IsZero() = %v", v.OnesCount(), v.IsZero())
+	}
+	w := v.Clone()
+	w.Xor(v)
+	if !w.IsZero() || v.IsZero() {
+		t.Error("Xor with itself should clear only the clone")
+	}
+	if got := vectorOf("1011").Format(4); got != "1011" {
+		t.Errorf("Format = %s", got)
+	}
+}
+
+func TestRankAndNullSpace(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Matrix
+		rank int
+	}{
+		{"identity", matrixOf("100", "010", "001"), 3},
+		{"zero", matrixOf("000", "000"), 0},
+		{"dependent rows", matrixOf("110", "011", "101"), 2},
+		{"wide", matrixOf("1100", "0110"), 2},
+		{"tall", matrixOf("10", "01", "11", "11"), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Rank(); got != tt.rank {
+				t.Errorf("Rank() = %d, want %d", got, tt.rank)
+			}
+			basis := tt.m.NullSpace()
+			if len(basis) != tt.m.Cols-tt.rank {
+				t.Errorf("%d null-space vectors, want %d", len(basis), tt.m.Cols-tt.rank)
+			}
+			for _, v := range basis {
+				if v.IsZero() || !tt.m.Apply(v).IsZero() {
+					t.Errorf("%s is not in the null space", v.Format(tt.m.Cols))
+				}
+			}
+		})
+	}
+}
+
+func TestSolve(t *testing.T) {
+	// The day 10 example: buttons (3) (1,3) (2) (2,3) (0,2) (0,1) as
+	// columns, lights as rows, target [.##.]
+	m := matrixOf(
+		"000011",
+		"010001",
+		"001110",
+		"110100",
+	)
+	b := vectorOf("0110")
+	x, basis, err := m.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Apply(x); got.Format(4) != "0110" {
+		t.Errorf("m x = %s, want 0110", got.Format(4))
+	}
+	if len(basis) != 2 {
+		t.Errorf("%d free variables, want 2", len(basis))
+	}
+
+	best, err := m.MinWeightSolution(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if best.OnesCount() != 2 {
+		t.Errorf("fewest presses %s, want 2 ones", best.Format(6))
+	}
+
+	_, _, err = matrixOf("11", "11").Solve(vectorOf("10"))
+	if !errors.Is(err, ErrNoSolution) {
+		t.Errorf("got %v, want ErrNoSolution", err)
+	}
+}
+
+func TestWideSystem(t *testing.T) {
+	// 100 equations x_i + x_(i+1) = b_i in 101 unknowns cross word
+	// boundaries on both sides
+	const n = 100
+	m := NewMatrix(n, n+1)
+	b := NewVector(n)
+	for i := 0; i < n; i++ {
+		m.Set(i, i)
+		m.Set(i, i+1)
+		if i%3 == 0 {
+			b.Set(i)
+		}
+	}
+	if m.Rank() != n {
+		t.Fatalf("Rank() = %d", m.Rank())
+	}
+	x, err := m.MinWeightSolution(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Apply(x); !slices.Equal(got, b) {
+		t.Errorf("m x = %s", got.Format(n))
+	}
+	// Both solutions are complements of each other; the lighter has at
+	// most half the bits
+	if x.OnesCount() > (n+1)/2 {
+		t.Errorf("weight %d is not the minimum", x.OnesCount())
+	}
+}
+
+func TestMinWeightMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for round := 0; round < 300; round++ {
+		rows, cols := 1+rng.Intn(8), 1+rng.Intn(10)
+		m := NewMatrix(rows, cols)
+		for r := 0; r < rows; r++ {
+			for c := 0; c < cols; c++ {
+				if rng.Intn(3) == 0 {
+					m.Set(r, c)
+				}
+			}
+		}
+		b := NewVector(rows)
+		for r := 0; r < rows; r++ {
+			if rng.Intn(2) == 0 {
+				b.Set(r)
+			}
+		}
+
+		want := -1
+		for mask := uint64(0); mask < 1<<cols; mask++ {
+			x := Vector{mask}
+			if slices.Equal(m.Apply(x), b) && (want < 0 || x.OnesCount() < want) {
+				want = x.OnesCount()
+			}
+		}
+
+		x, err := m.MinWeightSolution(b)
+		switch {
+		case want < 0 && !errors.Is(err, ErrNoSolution):
+			t.Fatalf("round %d: got %v, want ErrNoSolution", round, err)
+		case want >= 0 && err != nil:
+			t.Fatalf("round %d: %v", round, err)
+		case want >= 0 && (x.OnesCount() != want || !slices.Equal(m.Apply(x), b)):
+			t.Fatalf("round %d: got %s, want weight %d", round, x.Format(cols), want)
+		}
+	}
+}
+
+func TestMinWeightTooManyFree(t *testing.T) {
+	m := NewMatrix(1, MaxFree+2)
+	m.Set(0, 0)
+	_, err := m.MinWeightSolution(NewVector(1))
+	if err == nil || !strings.Contains(err.Error(), "free variables") {
+		t.Errorf("got %v", err)
+	}
+}
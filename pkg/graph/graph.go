@@ -0,0 +1,274 @@
+// Package graph searches graphs that are given implicitly: nodes are any
+// comparable value and edges come from a callback, so puzzles never have to
+// build an adjacency structure up front.
+package graph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+)
+
+// Next lists the nodes one step from n
+type Next[N comparable] func(n N) []N
+
+// Edge is a step to To costing Cost
+type Edge[N comparable] struct {
+	To   N
+	Cost int
+}
+
+// WeightedNext lists the edges out of n; costs must not be negative
+type WeightedNext[N comparable] func(n N) []Edge[N]
+
+// Path walks parent links back from end to the node without a parent and
+// returns the nodes from there to end
+func Path[N comparable](parent map[N]N, end N) []N {
+	path := []N{end}
+	for n, ok := parent[end]; ok; n, ok = parent[n] {
+		path = append(path, n)
+	}
+	reverse(path)
+	return path
+}
+
+func reverse[N any](s []N) {
+	for l, r := 0, len(s)-1; l < r; l, r = l+1, r-1 {
+		s[l], s[r] = s[r], s[l]
+	}
+}
+
+// Reachable lists every node reachable from start, start first, in
+// breadth-first order
+func Reachable[N comparable](start N, next Next[N]) []N {
+	seen := map[N]bool{start: true}
+	order := []N{start}
+	for head := 0; head < len(order); head++ {
+		for _, m := range next(order[head]) {
+			if !seen[m] {
+				seen[m] = true
+				order = append(order, m)
+			}
+		}
+	}
+	return order
+}
+
+// BFS returns a path with the fewest steps from start to a node where goal
+// holds, or false when there is none
+func BFS[N comparable](start N, next Next[N], goal func(N) bool) ([]N, bool) {
+	parent := map[N]N{}
+	seen := map[N]bool{start: true}
+	queue := []N{start}
+	for head := 0; head < len(queue); head++ {
+		n := queue[head]
+		if goal(n) {
+			return Path(parent, n), true
+		}
+		for _, m := range next(n) {
+			if !seen[m] {
+				seen[m] = true
+				parent[m] = n
+				queue = append(queue, m)
+			}
+		}
+	}
+	return nil, false
+}
+
+// Bidirectional returns a shortest path from start to goal by searching
+// forwards along next and backwards along prev, a level at a time from the
+// smaller frontier, until the two searches meet
+func Bidirectional[N comparable](start, goal N, next, prev Next[N]) ([]N, bool) {
+	if start == goal {
+		return []N{start}, true
+	}
+	fwd, back := map[N]N{}, map[N]N{}
+	fwdDist, backDist := map[N]int{start: 0}, map[N]int{goal: 0}
+	fwdLevel, backLevel := []N{start}, []N{goal}
+
+	for len(fwdLevel) > 0 && len(backLevel) > 0 {
+		forwards := len(fwdLevel) <= len(backLevel)
+		level, step, parent, dist, other := fwdLevel, next, fwd, fwdDist, backDist
+		if !forwards {
+			level, step, parent, dist, other = backLevel, prev, back, backDist, fwdDist
+		}
+
+		var nextLevel []N
+		var meet N
+		met := false
+		for _, n := range level {
+			for _, m := range step(n) {
+				if _, seen := dist[m]; seen {
+					continue
+				}
+				dist[m] = dist[n] + 1
+				parent[m] = n
+				nextLevel = append(nextLevel, m)
+				// The other side may have reached meeting nodes of this
+				// level at different depths; keep the nearest
+				if d, ok := other[m]; ok && (!met || d < other[meet]) {
+					meet, met = m, true
+				}
+			}
+		}
+		if met {
+			path := Path(fwd, meet)
+			tail := Path(back, meet)
+			reverse(tail)
+			return append(path, tail[1:]...), true
+		}
+		if forwards {
+			fwdLevel = nextLevel
+		} else {
+			backLevel = nextLevel
+		}
+	}
+	return nil, false
+}
+
+// Dijkstra returns a cheapest path from start to a node where goal holds
+// and its cost, or false when there is none
+func Dijkstra[N comparable](start N, next WeightedNext[N], goal func(N) bool) ([]N, int, bool) {
+	return AStar(start, next, goal, func(N) int { return 0 })
+}
+
+// AStar is Dijkstra guided by h, an estimate of the cost left from a node
+// to the goal. The path is cheapest when h never overestimates. A node
+// reached again more cheaply after it was expanded is expanded again, which
+// never happens when h is also consistent: h(n) <= cost(n, m) + h(m) on
+// every edge.
+func AStar[N comparable](start N, next WeightedNext[N], goal func(N) bool, h func(N) int) ([]N, int, bool) {
+	parent := map[N]N{}
+	cost := map[N]int{start: 0}
+	open := &frontier[N]{{node: start, cost: 0, priority: h(start)}}
+	for open.Len() > 0 {
+		it := heap.Pop(open).(item[N])
+		n := it.node
+		// Skip entries left behind by a cheaper way in
+		if it.cost > cost[n] {
+			continue
+		}
+		if goal(n) {
+			return Path(parent, n), cost[n], true
+		}
+		for _, e := range next(n) {
+			c := cost[n] + e.Cost
+			if old, ok := cost[e.To]; ok && old <= c {
+				continue
+			}
+			cost[e.To] = c
+			parent[e.To] = n
+			heap.Push(open, item[N]{node: e.To, cost: c, priority: c + h(e.To)})
+		}
+	}
+	return nil, 0, false
+}
+
+type item[N comparable] struct {
+	node     N
+	cost     int // cost of the path to node when it was pushed
+	priority int
+}
+
+// frontier is a min-heap of items by priority
+type frontier[N comparable] []item[N]
+
+func (f frontier[N]) Len() int           { return len(f) }
+func (f frontier[N]) Less(i, j int) bool { return f[i].priority < f[j].priority }
+func (f frontier[N]) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f *frontier[N]) Push(x any)        { *f = append(*f, x.(item[N])) }
+func (f *frontier[N]) Pop() any {
+	old := *f
+	x := old[len(old)-1]
+	*f = old[:len(old)-1]
+	return x
+}
+
+// ErrCycle is returned by TopoSort when the graph has a cycle
+var ErrCycle = errors.New("graph: cycle")
+
+// TopoSort orders nodes and everything reachable from them so that every
+// edge points forwards. Nodes come out in the order given where edges
+// allow it.
+func TopoSort[N comparable](nodes []N, next Next[N]) ([]N, error) {
+	const (
+		unseen = iota
+		open
+		closed
+	)
+	state := map[N]int{}
+	var order []N
+	var visit func(n N) error
+	visit = func(n N) error {
+		switch state[n] {
+		case open:
+			return fmt.Errorf("%w through %v", ErrCycle, n)
+		case closed:
+			return nil
+		}
+		state[n] = open
+		for _, m := range next(n) {
+			if err := visit(m); err != nil {
+				return err
+			}
+		}
+		state[n] = closed
+		order = append(order, n)
+		return nil
+	}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if err := visit(nodes[i]); err != nil {
+			return nil, err
+		}
+	}
+	reverse(order)
+	return order, nil
+}
+
+// SCC splits nodes and everything reachable from them into strongly
+// connected components with Tarjan's algorithm. Components come out in
+// reverse topological order: no edge leads to a later component.
+func SCC[N comparable](nodes []N, next Next[N]) [][]N {
+	index := map[N]int{}
+	low := map[N]int{}
+	onStack := map[N]bool{}
+	var stack []N
+	var components [][]N
+
+	var connect func(n N)
+	connect = func(n N) {
+		index[n] = len(index)
+		low[n] = index[n]
+		stack = append(stack, n)
+		onStack[n] = true
+		for _, m := range next(n) {
+			if _, seen := index[m]; !seen {
+				connect(m)
+				low[n] = min(low[n], low[m])
+			} else if onStack[m] {
+				low[n] = min(low[n], index[m])
+			}
+		}
+		if low[n] != index[n] {
+			return
+		}
+		var component []N
+		for {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[m] = false
+			component = append(component, m)
+			if m == n {
+				break
+			}
+		}
+		components = append(components, component)
+	}
+	for _, n := range nodes {
+		if _, seen := index[n]; !seen {
+			connect(n)
+		}
+	}
+	return components
+}
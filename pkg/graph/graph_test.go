@@ -0,0 +1,216 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// adjacency turns a map into a Next
+func adjacency(edges map[string][]string) Next[string] {
+	return func(n string) []string { return edges[n] }
+}
+
+func reversed(edges map[string][]string) Next[string] {
+	back := map[string][]string{}
+	for from, tos := range edges {
+		for _, to := range tos {
+			back[to] = append(back[to], from)
+		}
+	}
+	return adjacency(back)
+}
+
+func is[N comparable](want N) func(N) bool {
+	return func(n N) bool { return n == want }
+}
+
+var sample = map[string][]string{
+	"a": {"b", "c"},
+	"b": {"d"},
+	"c": {"d", "e"},
+	"d": {"f"},
+	"e": {"f"},
+	"f": {},
+	"x": {"a"},
+}
+
+func TestReachable(t *testing.T) {
+	got := Reachable("a", adjacency(sample))
+	if want := []string{"a", "b", "c", "d", "e", "f"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBFS(t *testing.T) {
+	path, ok := BFS("a", adjacency(sample), is("f"))
+	if want := []string{"a", "b", "d", "f"}; !ok || !slices.Equal(path, want) {
+		t.Errorf("got %v, %v; want %v", path, ok, want)
+	}
+	if path, ok := BFS("a", adjacency(sample), is("x")); ok {
+		t.Errorf("x is upstream of a, got %v", path)
+	}
+	if path, ok := BFS("a", adjacency(sample), is("a")); !ok || !slices.Equal(path, []string{"a"}) {
+		t.Errorf("got %v, %v", path, ok)
+	}
+}
+
+func TestBidirectional(t *testing.T) {
+	path, ok := Bidirectional("x", "f", adjacency(sample), reversed(sample))
+	if !ok || len(path) != 5 || path[0] != "x" || path[4] != "f" {
+		t.Fatalf("got %v, %v", path, ok)
+	}
+	for i := 1; i < len(path); i++ {
+		if !slices.Contains(sample[path[i-1]], path[i]) {
+			t.Errorf("%v steps from %s to %s without an edge", path, path[i-1], path[i])
+		}
+	}
+	if _, ok := Bidirectional("f", "a", adjacency(sample), reversed(sample)); ok {
+		t.Error("f has no way out")
+	}
+}
+
+// TestBidirectionalMatchesBFS compares path lengths on random digraphs
+func TestBidirectionalMatchesBFS(t *testing.T) {
+	rng := rand.New(rand.NewSource(45))
+	for round := 0; round < 200; round++ {
+		n := 2 + rng.Intn(30)
+		edges := map[int][]int{}
+		back := map[int][]int{}
+		for e := rng.Intn(3 * n); e > 0; e-- {
+			a, b := rng.Intn(n), rng.Intn(n)
+			edges[a] = append(edges[a], b)
+			back[b] = append(back[b], a)
+		}
+		next := func(v int) []int { return edges[v] }
+		prev := func(v int) []int { return back[v] }
+		from, to := rng.Intn(n), rng.Intn(n)
+
+		want, wantOK := BFS(from, next, is(to))
+		got, ok := Bidirectional(from, to, next, prev)
+		if ok != wantOK || len(got) != len(want) {
+			t.Fatalf("round %d: %d to %d: got %v, want %v", round, from, to, got, want)
+		}
+	}
+}
+
+// grid is a 5x5 grid of 1 cost cells with a wall of 9s down the middle,
+// open at the bottom
+var grid = []string{
+	"11911",
+	"11911",
+	"11911",
+	"11911",
+	"11111",
+}
+
+type cell struct{ r, c int }
+
+func gridNext(n cell) []Edge[cell] {
+	var edges []Edge[cell]
+	for _, d := range []cell{{0, 1}, {1, 0}, {0, -1}, {-1, 0}} {
+		m := cell{n.r + d.r, n.c + d.c}
+		if m.r >= 0 && m.r < len(grid) && m.c >= 0 && m.c < len(grid[0]) {
+			edges = append(edges, Edge[cell]{To: m, Cost: int(grid[m.r][m.c] - '0')})
+		}
+	}
+	return edges
+}
+
+func TestDijkstraAndAStar(t *testing.T) {
+	start, end := cell{0, 0}, cell{0, 4}
+	// Over the wall: 1 + 9 + 1 + 1 = 12; around it through the bottom: 12
+	// as well, so only the cost is pinned down
+	path, cost, ok := Dijkstra(start, gridNext, is(end))
+	if !ok || cost != 12 || path[0] != start || path[len(path)-1] != end {
+		t.Errorf("Dijkstra: %v, %d, %v", path, cost, ok)
+	}
+
+	manhattan := func(n cell) int { return abs(n.r-end.r) + abs(n.c-end.c) }
+	path, cost, ok = AStar(start, gridNext, is(end), manhattan)
+	if !ok || cost != 12 || path[len(path)-1] != end {
+		t.Errorf("AStar: %v, %d, %v", path, cost, ok)
+	}
+
+	if _, _, ok := Dijkstra(start, gridNext, is(cell{9, 9})); ok {
+		t.Error("found a cell off the grid")
+	}
+}
+
+// TestAStarInconsistentHeuristic uses an h that never overestimates but
+// drops by more than an edge costs from a to c, so c is first expanded by
+// the dearer way through b
+func TestAStarInconsistentHeuristic(t *testing.T) {
+	edges := map[string][]Edge[string]{
+		"s": {{To: "a", Cost: 1}, {To: "b", Cost: 1}},
+		"a": {{To: "c", Cost: 1}},
+		"b": {{To: "c", Cost: 3}},
+		"c": {{To: "g", Cost: 5}},
+	}
+	h := map[string]int{"a": 4}
+	path, cost, ok := AStar("s", func(n string) []Edge[string] { return edges[n] }, is("g"),
+		func(n string) int { return h[n] })
+	if !ok || cost != 7 || !slices.Equal(path, []string{"s", "a", "c", "g"}) {
+		t.Errorf("AStar: %v, %d, %v", path, cost, ok)
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func TestTopoSort(t *testing.T) {
+	order, err := TopoSort([]string{"x"}, adjacency(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"x", "a", "b", "c", "d", "e", "f"}; len(order) != len(want) {
+		t.Fatalf("got %v", order)
+	}
+	pos := map[string]int{}
+	for i, n := range order {
+		pos[n] = i
+	}
+	for from, tos := range sample {
+		for _, to := range tos {
+			if pos[from] > pos[to] {
+				t.Errorf("%s comes after %s in %v", from, to, order)
+			}
+		}
+	}
+
+	cyclic := map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}}
+	if _, err := TopoSort([]string{"a"}, adjacency(cyclic)); !errors.Is(err, ErrCycle) {
+		t.Errorf("got %v, want ErrCycle", err)
+	}
+}
+
+func TestSCC(t *testing.T) {
+	edges := map[string][]string{
+		"a": {"b"},
+		"b": {"c", "d"},
+		"c": {"a"},
+		"d": {"e"},
+		"e": {"d"},
+		"f": {"f"},
+	}
+	components := SCC([]string{"a", "f"}, adjacency(edges))
+	for _, c := range components {
+		slices.Sort(c)
+	}
+	want := [][]string{{"d", "e"}, {"a", "b", "c"}, {"f"}}
+	if !slices.EqualFunc(components, want, slices.Equal) {
+		t.Errorf("got %v, want %v", components, want)
+	}
+}
+
+func TestPath(t *testing.T) {
+	parent := map[int]int{3: 2, 2: 1}
+	if got := Path(parent, 3); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,319 @@
+// Package grid holds rectangular 2D grids of any cell type, read from and
+// written back to puzzle text. Cells are addressed by geometry.Point with X
+// the column and Y the row, both from 0 at the top left.
+//
+// A Grid does no locking: once it is built, any number of goroutines may
+// read it at the same time. Writes need the caller to make sure nobody else
+// is reading or writing.
+package grid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"aoc/pkg/geometry"
+)
+
+// Point is a cell position
+type Point = geometry.Point
+
+// Grid is Width columns by Height rows of T. Dense grids keep every cell in
+// a slice; sparse grids keep only the cells set to something other than the
+// zero value, for large grids that are mostly empty.
+type Grid[T comparable] struct {
+	Width, Height int
+	dense         []T
+	sparse        map[int]T // used instead of dense when not nil
+}
+
+// New returns a dense width x height grid of zero values
+func New[T comparable](width, height int) *Grid[T] {
+	return &Grid[T]{Width: width, Height: height, dense: make([]T, width*height)}
+}
+
+// NewSparse returns a sparse width x height grid of zero values
+func NewSparse[T comparable](width, height int) *Grid[T] {
+	return &Grid[T]{Width: width, Height: height, sparse: make(map[int]T)}
+}
+
+// empty is a grid of the same kind as g
+func (g *Grid[T]) empty(width, height int) *Grid[T] {
+	if g.sparse != nil {
+		return NewSparse[T](width, height)
+	}
+	return New[T](width, height)
+}
+
+// In reports whether p is on the grid
+func (g *Grid[T]) In(p Point) bool {
+	return p.X >= 0 && p.X < g.Width && p.Y >= 0 && p.Y < g.Height
+}
+
+// Get returns the cell at p, or the zero value off the grid
+func (g *Grid[T]) Get(p Point) T {
+	var zero T
+	if !g.In(p) {
+		return zero
+	}
+	i := p.Y*g.Width + p.X
+	if g.sparse != nil {
+		return g.sparse[i]
+	}
+	return g.dense[i]
+}
+
+// Set stores v at p, which must be on the grid
+func (g *Grid[T]) Set(p Point, v T) {
+	if !g.In(p) {
+		panic(fmt.Sprintf("grid: %d,%d is off the %dx%d grid", p.X, p.Y, g.Width, g.Height))
+	}
+	i := p.Y*g.Width + p.X
+	if g.sparse == nil {
+		g.dense[i] = v
+		return
+	}
+	var zero T
+	if v == zero {
+		delete(g.sparse, i)
+	} else {
+		g.sparse[i] = v
+	}
+}
+
+// All yields every cell in reading order, row by row. A sparse grid only
+// yields the cells set to something other than the zero value.
+func (g *Grid[T]) All() iter.Seq2[Point, T] {
+	return func(yield func(Point, T) bool) {
+		if g.sparse != nil {
+			for _, i := range g.stored() {
+				if !yield(g.point(i), g.sparse[i]) {
+					return
+				}
+			}
+			return
+		}
+		for i, v := range g.dense {
+			if !yield(g.point(i), v) {
+				return
+			}
+		}
+	}
+}
+
+// stored lists the indices held by a sparse grid in reading order
+func (g *Grid[T]) stored() []int {
+	return slices.Sorted(maps.Keys(g.sparse))
+}
+
+// point is the cell at index i
+func (g *Grid[T]) point(i int) Point {
+	return Point{X: i % g.Width, Y: i / g.Width}
+}
+
+// Count is the number of cells holding v
+func (g *Grid[T]) Count(v T) int {
+	var zero T
+	if g.sparse != nil && v == zero {
+		return g.Width*g.Height - len(g.sparse)
+	}
+	n := 0
+	for _, c := range g.All() {
+		if c == v {
+			n++
+		}
+	}
+	return n
+}
+
+var (
+	orthogonal = []Point{{X: 0, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}}
+	diagonal   = []Point{{X: 1, Y: -1}, {X: 1, Y: 1}, {X: -1, Y: 1}, {X: -1, Y: -1}}
+)
+
+// Neighbors4 yields the cells up, right, down and left of p that are on the
+// grid
+func (g *Grid[T]) Neighbors4(p Point) iter.Seq[Point] {
+	return g.neighbors(p, orthogonal, nil)
+}
+
+// Neighbors8 is Neighbors4 followed by the four diagonal neighbours
+func (g *Grid[T]) Neighbors8(p Point) iter.Seq[Point] {
+	return g.neighbors(p, orthogonal, diagonal)
+}
+
+func (g *Grid[T]) neighbors(p Point, offsets ...[]Point) iter.Seq[Point] {
+	return func(yield func(Point) bool) {
+		for _, set := range offsets {
+			for _, d := range set {
+				n := Point{X: p.X + d.X, Y: p.Y + d.Y}
+				if g.In(n) && !yield(n) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Row returns a copy of row y
+func (g *Grid[T]) Row(y int) []T {
+	row := make([]T, g.Width)
+	for x := range row {
+		row[x] = g.Get(Point{X: x, Y: y})
+	}
+	return row
+}
+
+// Col returns a copy of column x, top to bottom
+func (g *Grid[T]) Col(x int) []T {
+	col := make([]T, g.Height)
+	for y := range col {
+		col[y] = g.Get(Point{X: x, Y: y})
+	}
+	return col
+}
+
+// Rows returns a copy of the grid as a slice of rows
+func (g *Grid[T]) Rows() [][]T {
+	rows := make([][]T, g.Height)
+	for y := range rows {
+		rows[y] = g.Row(y)
+	}
+	return rows
+}
+
+// Clone returns an independent copy of g
+func (g *Grid[T]) Clone() *Grid[T] {
+	return g.remap(g.Width, g.Height, func(p Point) Point { return p })
+}
+
+// remap builds a width x height grid with g's cell at p moved to to(p).
+// Only the stored cells of a sparse grid are moved.
+func (g *Grid[T]) remap(width, height int, to func(Point) Point) *Grid[T] {
+	out := g.empty(width, height)
+	for p, v := range g.All() {
+		out.Set(to(p), v)
+	}
+	return out
+}
+
+// Transpose swaps rows and columns
+func (g *Grid[T]) Transpose() *Grid[T] {
+	return g.remap(g.Height, g.Width, func(p Point) Point { return Point{X: p.Y, Y: p.X} })
+}
+
+// Rotate turns the grid a quarter turn clockwise
+func (g *Grid[T]) Rotate() *Grid[T] {
+	return g.remap(g.Height, g.Width, func(p Point) Point { return Point{X: g.Height - 1 - p.Y, Y: p.X} })
+}
+
+// FlipH mirrors the grid left to right
+func (g *Grid[T]) FlipH() *Grid[T] {
+	return g.remap(g.Width, g.Height, func(p Point) Point { return Point{X: g.Width - 1 - p.X, Y: p.Y} })
+}
+
+// FlipV mirrors the grid top to bottom
+func (g *Grid[T]) FlipV() *Grid[T] {
+	return g.remap(g.Width, g.Height, func(p Point) Point { return Point{X: p.X, Y: g.Height - 1 - p.Y} })
+}
+
+// Format writes the grid back as text, one line per row and no newline at
+// the end, with cell turning each cell into its text. A sparse grid fills
+// the gaps between its stored cells with the zero value's text.
+func (g *Grid[T]) Format(cell func(Point, T) string) string {
+	var sb strings.Builder
+	var zero T
+	next := 0
+	// gap writes the cells from next up to index end
+	gap := func(end int) {
+		for ; next < end; next++ {
+			p := g.point(next)
+			if p.X == 0 && p.Y > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(cell(p, zero))
+		}
+	}
+	for p, v := range g.All() {
+		i := p.Y*g.Width + p.X
+		gap(i)
+		if p.X == 0 && p.Y > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(cell(p, v))
+		next = i + 1
+	}
+	gap(g.Width * g.Height)
+	return sb.String()
+}
+
+// Parse reads one row per line with cell turning each rune into a value.
+// Blank lines are skipped and every other line must be as long as the
+// first.
+func Parse[T comparable](r io.Reader, cell func(rune) (T, error)) (*Grid[T], error) {
+	var rows [][]T
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var row []T
+		for col, c := range []rune(line) {
+			v, err := cell(c)
+			if err != nil {
+				return nil, fmt.Errorf("line %d column %d: %w", lineNo, col+1, err)
+			}
+			row = append(row, v)
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("line %d: %d cells, want %d", lineNo, len(row), len(rows[0]))
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	g := New[T](0, len(rows))
+	if len(rows) > 0 {
+		g = New[T](len(rows[0]), len(rows))
+	}
+	for y, row := range rows {
+		copy(g.dense[y*g.Width:], row)
+	}
+	return g, nil
+}
+
+// Read is Parse on a file
+func Read[T comparable](filename string, cell func(rune) (T, error)) (*Grid[T], error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	g, err := Parse(f, cell)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return g, nil
+}
+
+// Digit maps '0' to '9' to their values
+func Digit(c rune) (int, error) {
+	if c < '0' || c > '9' {
+		return 0, fmt.Errorf("%q is not a digit", c)
+	}
+	return int(c - '0'), nil
+}
+
+// Rune keeps each character as it is
+func Rune(c rune) (rune, error) {
+	return c, nil
+}
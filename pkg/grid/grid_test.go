@@ -0,0 +1,196 @@
+package grid
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func parse(t *testing.T, text string) *Grid[rune] {
+	t.Helper()
+	g, err := Parse(strings.NewReader(text), Rune)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func text(g *Grid[rune]) string {
+	return g.Format(func(_ Point, c rune) string { return string(c) })
+}
+
+func TestParseAndFormat(t *testing.T) {
+	g := parse(t, "abc\n\ndef\n")
+	if g.Width != 3 || g.Height != 2 {
+		t.Fatalf("got %dx%d", g.Width, g.Height)
+	}
+	if got := g.Get(Point{X: 2, Y: 1}); got != 'f' {
+		t.Errorf("Get(2,1) = %q", got)
+	}
+	if got := g.Get(Point{X: 3, Y: 0}); got != 0 {
+		t.Errorf("off the grid gave %q", got)
+	}
+	if got := text(g); got != "abc\ndef" {
+		t.Errorf("Format = %q", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse(strings.NewReader("123\n45\n"), Digit); err == nil || err.Error() != "line 2: 2 cells, want 3" {
+		t.Errorf("ragged: %v", err)
+	}
+	if _, err := Parse(strings.NewReader("123\n4x6\n"), Digit); err == nil || !strings.HasPrefix(err.Error(), "line 2 column 2:") {
+		t.Errorf("bad digit: %v", err)
+	}
+	filename := filepath.Join(t.TempDir(), "grid.txt")
+	if err := os.WriteFile(filename, []byte("12\n3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(filename, Digit); err == nil || !strings.HasPrefix(err.Error(), filename+": line 2") {
+		t.Errorf("Read: %v", err)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	g := New[int](3, 3)
+	corner := slices.Collect(g.Neighbors4(Point{X: 0, Y: 0}))
+	if want := []Point{{X: 1, Y: 0}, {X: 0, Y: 1}}; !slices.Equal(corner, want) {
+		t.Errorf("Neighbors4(corner) = %v", corner)
+	}
+	if n := len(slices.Collect(g.Neighbors8(Point{X: 1, Y: 1}))); n != 8 {
+		t.Errorf("middle has %d neighbours", n)
+	}
+	if n := len(slices.Collect(g.Neighbors8(Point{X: 2, Y: 1}))); n != 5 {
+		t.Errorf("edge has %d neighbours", n)
+	}
+	// Stopping early must not panic
+	for range g.Neighbors8(Point{X: 1, Y: 1}) {
+		break
+	}
+}
+
+func TestRowsAndCols(t *testing.T) {
+	g, err := Parse(strings.NewReader("123\n456\n"), Digit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Row(1); !slices.Equal(got, []int{4, 5, 6}) {
+		t.Errorf("Row(1) = %v", got)
+	}
+	if got := g.Col(2); !slices.Equal(got, []int{3, 6}) {
+		t.Errorf("Col(2) = %v", got)
+	}
+	if got := g.Rows(); !reflect.DeepEqual(got, [][]int{{1, 2, 3}, {4, 5, 6}}) {
+		t.Errorf("Rows() = %v", got)
+	}
+	g.Row(0)[0] = 9
+	if g.Get(Point{}) != 1 {
+		t.Error("Row shares memory with the grid")
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	g := parse(t, "abc\ndef")
+	tests := []struct {
+		name string
+		got  *Grid[rune]
+		want string
+	}{
+		{"transpose", g.Transpose(), "ad\nbe\ncf"},
+		{"rotate", g.Rotate(), "da\neb\nfc"},
+		{"rotate twice", g.Rotate().Rotate(), "fed\ncba"},
+		{"rotate four times", g.Rotate().Rotate().Rotate().Rotate(), "abc\ndef"},
+		{"flip left to right", g.FlipH(), "cba\nfed"},
+		{"flip top to bottom", g.FlipV(), "def\nabc"},
+	}
+	for _, tt := range tests {
+		if got := text(tt.got); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if text(g) != "abc\ndef" {
+		t.Error("transforms changed the original")
+	}
+}
+
+func TestSparse(t *testing.T) {
+	g := NewSparse[bool](1_000_000, 1_000_000)
+	far := Point{X: 999_999, Y: 123_456}
+	g.Set(far, true)
+	if !g.Get(far) || g.Get(Point{X: 5, Y: 5}) {
+		t.Error("sparse lookups wrong")
+	}
+	// None of these may walk the trillion cells
+	if g.Count(true) != 1 || g.Count(false) != 1_000_000*1_000_000-1 {
+		t.Errorf("Count(true) = %d, Count(false) = %d", g.Count(true), g.Count(false))
+	}
+	if !g.Rotate().Get(Point{X: 1_000_000 - 1 - far.Y, Y: far.X}) || !g.Clone().Get(far) {
+		t.Error("sparse rotate or clone lost the cell")
+	}
+	for p, v := range g.All() {
+		if p != far || !v {
+			t.Errorf("All yielded %v, %v", p, v)
+		}
+	}
+	g.Set(far, false)
+	if len(g.sparse) != 0 {
+		t.Errorf("zero values are stored: %v", g.sparse)
+	}
+
+	small := NewSparse[int](3, 2)
+	small.Set(Point{X: 2, Y: 0}, 7)
+	small.Set(Point{X: 0, Y: 1}, 4)
+	if got := small.Format(func(_ Point, v int) string { return strconv.Itoa(v) }); got != "007\n400" {
+		t.Errorf("sparse format = %q", got)
+	}
+	small.Set(Point{X: 0, Y: 1}, 0)
+	if got := small.Transpose().Format(func(_ Point, v int) string { return strconv.Itoa(v) }); got != "00\n00\n70" {
+		t.Errorf("sparse transpose = %q", got)
+	}
+	if small.Transpose().sparse == nil {
+		t.Error("transpose of a sparse grid is dense")
+	}
+	if small.Count(7) != 1 || small.Count(0) != 5 {
+		t.Errorf("Count(7) = %d, Count(0) = %d", small.Count(7), small.Count(0))
+	}
+}
+
+func TestSetOffGridPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic")
+		}
+	}()
+	New[int](2, 2).Set(Point{X: 2, Y: 0}, 1)
+}
+
+// TestConcurrentReads is meant for go test -race: readers share a built
+// grid without any locking
+func TestConcurrentReads(t *testing.T) {
+	for _, g := range []*Grid[int]{New[int](50, 50), NewSparse[int](50, 50)} {
+		for y := 0; y < g.Height; y++ {
+			for x := 0; x < g.Width; x++ {
+				g.Set(Point{X: x, Y: y}, x*y)
+			}
+		}
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for p, v := range g.All() {
+					if g.Get(p) != v || v != p.X*p.Y {
+						t.Errorf("%v: %d", p, v)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,142 @@
+// Package harness runs alternative solutions for the same puzzle part
+// against each other and reports where they disagree.
+package harness
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Key identifies one part of one day's puzzle
+type Key struct {
+	Year, Day, Part int
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%d day %d part %d", k.Year, k.Day, k.Part)
+}
+
+// Implementation is one named solution of a puzzle part
+type Implementation[In any, Out comparable] struct {
+	Name  string
+	Solve func(In) Out
+}
+
+// Result is what one implementation answered
+type Result[Out comparable] struct {
+	Name   string
+	Answer Out
+}
+
+// Disagreement is an input on which the implementations do not all give
+// the same answer
+type Disagreement[In any, Out comparable] struct {
+	Key     Key
+	Input   In
+	Results []Result[Out]
+	Shrinks int // successful shrink steps taken to reach Input
+}
+
+func (d *Disagreement[In, Out]) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v: implementations disagree", d.Key)
+	if d.Shrinks > 0 {
+		fmt.Fprintf(&sb, " (shrunk %d times)", d.Shrinks)
+	}
+	fmt.Fprintf(&sb, "\ninput: %v", d.Input)
+	for _, r := range d.Results {
+		fmt.Fprintf(&sb, "\n  %s: %v", r.Name, r.Answer)
+	}
+	return sb.String()
+}
+
+// Registry holds the implementations of puzzle parts that share an input
+// and answer type
+type Registry[In any, Out comparable] struct {
+	impls map[Key][]Implementation[In, Out]
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry[In any, Out comparable]() *Registry[In, Out] {
+	return &Registry[In, Out]{impls: make(map[Key][]Implementation[In, Out])}
+}
+
+// Register adds an implementation for key; names must be unique per key
+func (r *Registry[In, Out]) Register(key Key, name string, solve func(In) Out) {
+	for _, impl := range r.impls[key] {
+		if impl.Name == name {
+			panic(fmt.Sprintf("harness: %v already has an implementation %q", key, name))
+		}
+	}
+	r.impls[key] = append(r.impls[key], Implementation[In, Out]{Name: name, Solve: solve})
+}
+
+// Implementations lists what is registered for key, in registration order
+func (r *Registry[In, Out]) Implementations(key Key) []Implementation[In, Out] {
+	return r.impls[key]
+}
+
+// Compare runs every implementation of key on input and returns nil when
+// they all agree
+func (r *Registry[In, Out]) Compare(key Key, input In) *Disagreement[In, Out] {
+	impls := r.impls[key]
+	results := make([]Result[Out], len(impls))
+	agree := true
+	for i, impl := range impls {
+		results[i] = Result[Out]{Name: impl.Name, Answer: impl.Solve(input)}
+		if results[i].Answer != results[0].Answer {
+			agree = false
+		}
+	}
+	if agree {
+		return nil
+	}
+	return &Disagreement[In, Out]{Key: key, Input: input, Results: results}
+}
+
+// Config drives a randomised comparison
+type Config[In any] struct {
+	Seed     int64
+	Rounds   int
+	Generate func(rng *rand.Rand) In
+	// Shrink proposes smaller variants of a failing input, most aggressive
+	// first. Every candidate must itself be a valid input. May be nil.
+	Shrink func(In) []In
+}
+
+// Check compares the implementations of key on cfg.Rounds generated inputs.
+// The first disagreement found is shrunk greedily: any candidate that still
+// makes the implementations disagree replaces it, until none does.
+func (r *Registry[In, Out]) Check(key Key, cfg Config[In]) *Disagreement[In, Out] {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	for round := 0; round < cfg.Rounds; round++ {
+		d := r.Compare(key, cfg.Generate(rng))
+		if d == nil {
+			continue
+		}
+		return r.shrink(key, d, cfg.Shrink)
+	}
+	return nil
+}
+
+func (r *Registry[In, Out]) shrink(key Key, d *Disagreement[In, Out], shrink func(In) []In) *Disagreement[In, Out] {
+	if shrink == nil {
+		return d
+	}
+	steps := 0
+	for {
+		var smaller *Disagreement[In, Out]
+		for _, candidate := range shrink(d.Input) {
+			if smaller = r.Compare(key, candidate); smaller != nil {
+				break
+			}
+		}
+		if smaller == nil {
+			d.Shrinks = steps
+			return d
+		}
+		steps++
+		d = smaller
+	}
+}
@@ -0,0 +1,106 @@
+package harness
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var sumKey = Key{Year: 2000, Day: 1, Part: 1}
+
+func sum(xs []int) int {
+	total := 0
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+// buggySum drops any 7 it sees
+func buggySum(xs []int) int {
+	total := 0
+	for _, x := range xs {
+		if x != 7 {
+			total += x
+		}
+	}
+	return total
+}
+
+func randomInts(rng *rand.Rand) []int {
+	xs := make([]int, 1+rng.Intn(20))
+	for i := range xs {
+		xs[i] = rng.Intn(10)
+	}
+	return xs
+}
+
+// dropOne proposes every list with a single element removed
+func dropOne(xs []int) [][]int {
+	var out [][]int
+	for i := range xs {
+		out = append(out, slices.Delete(slices.Clone(xs), i, i+1))
+	}
+	return out
+}
+
+func TestCompare(t *testing.T) {
+	r := NewRegistry[[]int, int]()
+	r.Register(sumKey, "loop", sum)
+	r.Register(sumKey, "buggy", buggySum)
+
+	if d := r.Compare(sumKey, []int{1, 2, 3}); d != nil {
+		t.Errorf("Compare() = %v, want agreement", d)
+	}
+	d := r.Compare(sumKey, []int{1, 7})
+	if d == nil {
+		t.Fatal("Compare() agreed, want a disagreement")
+	}
+	if d.Results[0] != (Result[int]{"loop", 8}) || d.Results[1] != (Result[int]{"buggy", 1}) {
+		t.Errorf("Compare() results = %v", d.Results)
+	}
+}
+
+func TestCheckShrinks(t *testing.T) {
+	r := NewRegistry[[]int, int]()
+	r.Register(sumKey, "loop", sum)
+	r.Register(sumKey, "buggy", buggySum)
+
+	d := r.Check(sumKey, Config[[]int]{Seed: 1, Rounds: 100, Generate: randomInts, Shrink: dropOne})
+	if d == nil {
+		t.Fatal("Check() found no disagreement")
+	}
+	if !slices.Equal(d.Input, []int{7}) {
+		t.Errorf("Check() shrunk to %v, want [7]", d.Input)
+	}
+	if !strings.Contains(d.String(), "buggy: 0") {
+		t.Errorf("String() = %q, want the buggy answer listed", d.String())
+	}
+}
+
+func TestCheckAgreement(t *testing.T) {
+	r := NewRegistry[[]int, int]()
+	r.Register(sumKey, "loop", sum)
+	r.Register(sumKey, "slices", func(xs []int) int {
+		total := 0
+		for x := range slices.Values(xs) {
+			total += x
+		}
+		return total
+	})
+	if d := r.Check(sumKey, Config[[]int]{Seed: 1, Rounds: 100, Generate: randomInts, Shrink: dropOne}); d != nil {
+		t.Errorf("Check() = %v, want agreement", d)
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	r := NewRegistry[[]int, int]()
+	r.Register(sumKey, "loop", sum)
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() of a duplicate name did not panic")
+		}
+	}()
+	r.Register(sumKey, "loop", sum)
+}
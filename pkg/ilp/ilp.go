@@ -0,0 +1,361 @@
+// Package ilp solves small integer linear programs exactly, without an
+// external solver: minimise a cost over whole-number unknowns subject to
+// linear equalities.
+//
+// The equalities are brought into reduced row echelon form with exact
+// integer arithmetic, which writes every pivot unknown in terms of the free
+// ones. The free unknowns are then searched depth first inside their bounds.
+// A branch is cut as soon as some equation can no longer give a pivot
+// within its bounds, or the cost cannot beat the best answer so far. This
+// is fast when there are few free unknowns, as in puzzle inputs.
+package ilp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Problem is: minimise Cost·x subject to A x = B, with every x[j] a whole
+// number in [0, Upper[j]]
+type Problem struct {
+	A [][]int
+	B []int
+	// Cost has one entry per unknown; nil means every unknown costs 1
+	Cost []int
+	// Upper has one entry per unknown, negative for no bound; nil means no
+	// bounds. Bounds are also derived from rows of A without negative
+	// entries, and every free unknown needs one.
+	Upper []int
+}
+
+// Solution is an optimal assignment and its cost
+type Solution struct {
+	X    []int
+	Cost int
+}
+
+var (
+	// ErrInfeasible is returned when no whole-number x satisfies the problem
+	ErrInfeasible = errors.New("ilp: infeasible")
+	// ErrUnbounded is returned when a free unknown has no upper bound, so
+	// the search cannot be exhaustive
+	ErrUnbounded = errors.New("ilp: free unknown without an upper bound")
+)
+
+// Solve returns an optimal solution of p. Ties go to the solution found
+// first, which has the smallest free unknowns in lexicographic order.
+func Solve(p Problem) (Solution, error) {
+	n, err := p.unknowns()
+	if err != nil {
+		return Solution{}, err
+	}
+	upper, err := p.bounds(n)
+	if err != nil {
+		return Solution{}, err
+	}
+	cost := p.Cost
+	if cost == nil {
+		cost = make([]int, n)
+		for j := range cost {
+			cost[j] = 1
+		}
+	}
+
+	rows, pivots, err := reduce(p.A, p.B, n)
+	if err != nil {
+		return Solution{}, err
+	}
+	s := newSearch(rows, pivots, cost, upper, n)
+	for _, f := range s.free {
+		if upper[f] < 0 {
+			return Solution{}, fmt.Errorf("%w: x[%d]", ErrUnbounded, f)
+		}
+	}
+	s.dfs(0, s.costBase)
+	if s.best == nil {
+		return Solution{}, ErrInfeasible
+	}
+	return Solution{X: s.best, Cost: s.bestCost}, nil
+}
+
+// unknowns checks the shape of p and returns the number of unknowns
+func (p Problem) unknowns() (int, error) {
+	n := max(len(p.Cost), len(p.Upper))
+	if len(p.A) > 0 {
+		n = max(n, len(p.A[0]))
+	}
+	if len(p.B) != len(p.A) {
+		return 0, fmt.Errorf("ilp: %d rows but %d right-hand sides", len(p.A), len(p.B))
+	}
+	for i, row := range p.A {
+		if len(row) != n {
+			return 0, fmt.Errorf("ilp: row %d has %d entries, want %d", i, len(row), n)
+		}
+	}
+	if p.Cost != nil && len(p.Cost) != n {
+		return 0, fmt.Errorf("ilp: %d costs, want %d", len(p.Cost), n)
+	}
+	if p.Upper != nil && len(p.Upper) != n {
+		return 0, fmt.Errorf("ilp: %d upper bounds, want %d", len(p.Upper), n)
+	}
+	return n, nil
+}
+
+// bounds merges the given upper bounds with those implied by rows whose
+// entries are all non-negative: there x[j] <= B[i] / A[i][j]
+func (p Problem) bounds(n int) ([]int, error) {
+	upper := make([]int, n)
+	for j := range upper {
+		upper[j] = -1
+		if p.Upper != nil {
+			upper[j] = p.Upper[j]
+		}
+	}
+	for i, row := range p.A {
+		nonNegative := true
+		for _, a := range row {
+			if a < 0 {
+				nonNegative = false
+				break
+			}
+		}
+		if !nonNegative {
+			continue
+		}
+		if p.B[i] < 0 {
+			return nil, ErrInfeasible
+		}
+		for j, a := range row {
+			if a > 0 && (upper[j] < 0 || p.B[i]/a < upper[j]) {
+				upper[j] = p.B[i] / a
+			}
+		}
+	}
+	return upper, nil
+}
+
+// reduce returns the rows of [A | B] in reduced row echelon form with
+// integer entries, one row per pivot with a positive pivot entry, and the
+// pivot column of each row
+func reduce(a [][]int, b []int, n int) ([][]int, []int, error) {
+	m := make([][]int, len(a))
+	for i, row := range a {
+		m[i] = append(append(make([]int, 0, n+1), row...), b[i])
+	}
+	var pivots []int
+	rank := 0
+	for c := 0; c < n && rank < len(m); c++ {
+		pivot := -1
+		for r := rank; r < len(m); r++ {
+			if m[r][c] != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		m[rank], m[pivot] = m[pivot], m[rank]
+		if m[rank][c] < 0 {
+			scale(m[rank], -1)
+		}
+		for r := range m {
+			if r == rank || m[r][c] == 0 {
+				continue
+			}
+			// r = r*pivot - rank*m[r][c] keeps everything whole
+			f, g := m[rank][c], m[r][c]
+			for k := range m[r] {
+				m[r][k] = m[r][k]*f - m[rank][k]*g
+			}
+			normalize(m[r])
+		}
+		pivots = append(pivots, c)
+		rank++
+	}
+	for _, row := range m[rank:] {
+		if row[n] != 0 {
+			return nil, nil, ErrInfeasible
+		}
+	}
+	return m[:rank], pivots, nil
+}
+
+// normalize divides row by the gcd of its entries
+func normalize(row []int) {
+	g := 0
+	for _, v := range row {
+		g = gcd(g, v)
+	}
+	if g > 1 {
+		for k := range row {
+			row[k] /= g
+		}
+	}
+}
+
+func scale(row []int, f int) {
+	for k := range row {
+		row[k] *= f
+	}
+}
+
+func gcd(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// search holds the reduced problem as the free unknowns see it. Row i reads
+// d[i]*x[pivot[i]] + sum over free k of coef[i][k]*x[free[k]] = rhs[i].
+type search struct {
+	n      int
+	pivots []int
+	free   []int
+	d      []int
+	coef   [][]int
+	rhs    []int
+	cost   []int
+	upper  []int
+
+	// Per row, the least and greatest sum the free unknowns from k on can
+	// still add
+	remLo, remHi [][]int
+
+	// The cost times lcm is costBase plus gain[k]*x[free[k]] summed over k;
+	// minGain[k] is the least the free unknowns from k on can add to it.
+	// Pruning on cost needs every cost non-negative.
+	lcm      int
+	costBase int
+	gain     []int
+	minGain  []int
+	prune    bool
+	x        []int
+	best     []int
+	bestCost int
+}
+
+func newSearch(rows [][]int, pivots, cost, upper []int, n int) *search {
+	s := &search{n: n, pivots: pivots, cost: cost, upper: upper, x: make([]int, n), lcm: 1, prune: true}
+	isPivot := make([]bool, n)
+	for _, c := range pivots {
+		isPivot[c] = true
+	}
+	for j := 0; j < n; j++ {
+		if !isPivot[j] {
+			s.free = append(s.free, j)
+		}
+		if cost[j] < 0 {
+			s.prune = false
+		}
+	}
+	for i, row := range rows {
+		s.d = append(s.d, row[pivots[i]])
+		s.rhs = append(s.rhs, row[n])
+		coef := make([]int, len(s.free))
+		for k, f := range s.free {
+			coef[k] = row[f]
+		}
+		s.coef = append(s.coef, coef)
+		s.lcm = s.lcm / gcd(s.lcm, s.d[i]) * s.d[i]
+	}
+
+	s.remLo = make([][]int, len(rows))
+	s.remHi = make([][]int, len(rows))
+	for i := range rows {
+		lo, hi := make([]int, len(s.free)+1), make([]int, len(s.free)+1)
+		for k := len(s.free) - 1; k >= 0; k-- {
+			v := s.coef[i][k] * max(upper[s.free[k]], 0)
+			lo[k], hi[k] = lo[k+1]+min(v, 0), hi[k+1]+max(v, 0)
+		}
+		s.remLo[i], s.remHi[i] = lo, hi
+	}
+
+	s.gain = make([]int, len(s.free))
+	for k, f := range s.free {
+		s.gain[k] = cost[f] * s.lcm
+	}
+	for i, p := range pivots {
+		per := cost[p] * (s.lcm / s.d[i])
+		s.costBase += per * s.rhs[i]
+		for k := range s.free {
+			s.gain[k] -= per * s.coef[i][k]
+		}
+	}
+	s.minGain = make([]int, len(s.free)+1)
+	for k := len(s.free) - 1; k >= 0; k-- {
+		s.minGain[k] = s.minGain[k+1] + min(s.gain[k]*max(upper[s.free[k]], 0), 0)
+	}
+	return s
+}
+
+// dfs tries every value of free unknown k with the ones before it fixed;
+// scaled is the cost times lcm contributed so far
+func (s *search) dfs(k int, scaled int) {
+	if !s.feasible(k) {
+		return
+	}
+	if s.prune && s.best != nil && scaled+s.minGain[k] >= s.bestCost*s.lcm {
+		return
+	}
+	if k == len(s.free) {
+		s.finish()
+		return
+	}
+	f := s.free[k]
+	for v := 0; v <= s.upper[f]; v++ {
+		s.x[f] = v
+		s.dfs(k+1, scaled+s.gain[k]*v)
+	}
+	s.x[f] = 0
+}
+
+// feasible reports whether every row can still give its pivot a value in
+// bounds once the free unknowns from k on are chosen
+func (s *search) feasible(k int) bool {
+	for i := range s.d {
+		left := s.rhs[i]
+		for j := 0; j < k; j++ {
+			left -= s.coef[i][j] * s.x[s.free[j]]
+		}
+		// d*x[pivot] = left - rest with rest in [remLo, remHi]
+		lo, hi := left-s.remHi[i][k], left-s.remLo[i][k]
+		top := hi
+		if u := s.upper[s.pivots[i]]; u >= 0 {
+			top = min(top, s.d[i]*u)
+		}
+		if max(lo, 0) > top {
+			return false
+		}
+	}
+	return true
+}
+
+// finish works out the pivots with every free unknown fixed
+func (s *search) finish() {
+	total := 0
+	for i, p := range s.pivots {
+		left := s.rhs[i]
+		for k, f := range s.free {
+			left -= s.coef[i][k] * s.x[f]
+		}
+		if left%s.d[i] != 0 {
+			return
+		}
+		s.x[p] = left / s.d[i]
+	}
+	for j, v := range s.x {
+		total += s.cost[j] * v
+	}
+	if s.best == nil || total < s.bestCost {
+		s.best = append([]int(nil), s.x...)
+		s.bestCost = total
+	}
+}
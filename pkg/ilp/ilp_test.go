@@ -0,0 +1,218 @@
+package ilp
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Problem
+		cost int
+		err  error
+	}{
+		{
+			// The first day 10 example machine: buttons (3) (1,3) (2) (2,3)
+			// (0,2) (0,1) as columns, joltage {3,5,4,7}
+			name: "joltage example",
+			p: Problem{
+				A: [][]int{
+					{0, 0, 0, 0, 1, 1},
+					{0, 1, 0, 0, 0, 1},
+					{0, 0, 1, 1, 1, 0},
+					{1, 1, 0, 1, 0, 0},
+				},
+				B: []int{3, 5, 4, 7},
+			},
+			cost: 10,
+		},
+		{
+			name: "unique solution",
+			p:    Problem{A: [][]int{{1, 1}, {1, -1}}, B: []int{5, 1}, Upper: []int{10, 10}},
+			cost: 5,
+		},
+		{
+			name: "weighted cost prefers the cheap unknown",
+			p:    Problem{A: [][]int{{1, 1, 1}}, B: []int{4}, Cost: []int{3, 1, 2}},
+			cost: 4,
+		},
+		{
+			name: "duplicate rows",
+			p:    Problem{A: [][]int{{1, 2}, {1, 2}, {2, 4}}, B: []int{6, 6, 12}},
+			cost: 3,
+		},
+		{
+			name: "no equations",
+			p:    Problem{Upper: []int{3, 3}},
+			cost: 0,
+		},
+		{
+			name: "all zero right-hand side",
+			p:    Problem{A: [][]int{{1, 1, 0}, {0, 1, 1}}, B: []int{0, 0}},
+			cost: 0,
+		},
+		{
+			name: "no whole solution",
+			p:    Problem{A: [][]int{{2, 2}}, B: []int{3}},
+			err:  ErrInfeasible,
+		},
+		{
+			name: "contradicting rows",
+			p:    Problem{A: [][]int{{1, 1}, {1, 1}}, B: []int{2, 3}},
+			err:  ErrInfeasible,
+		},
+		{
+			name: "negative right-hand side",
+			p:    Problem{A: [][]int{{1, 1}}, B: []int{-1}},
+			err:  ErrInfeasible,
+		},
+		{
+			name: "upper bound rules it out",
+			p:    Problem{A: [][]int{{1, 0}, {0, 1}}, B: []int{2, 5}, Upper: []int{-1, 4}},
+			err:  ErrInfeasible,
+		},
+		{
+			name: "difference has no bound",
+			p:    Problem{A: [][]int{{1, -1}}, B: []int{2}},
+			err:  ErrUnbounded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sol, err := Solve(tt.p)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("got %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sol.Cost != tt.cost {
+				t.Errorf("cost %d (x = %v), want %d", sol.Cost, sol.X, tt.cost)
+			}
+			checkSolution(t, tt.p, sol)
+		})
+	}
+}
+
+func TestSolveShapeErrors(t *testing.T) {
+	tests := []struct {
+		p    Problem
+		want string
+	}{
+		{Problem{A: [][]int{{1, 1}}, B: []int{1, 2}}, "1 rows but 2 right-hand sides"},
+		{Problem{A: [][]int{{1, 1}, {1}}, B: []int{1, 2}}, "row 1 has 1 entries"},
+		{Problem{A: [][]int{{1, 1}}, B: []int{1}, Cost: []int{1}}, "1 costs, want 2"},
+	}
+	for _, tt := range tests {
+		if _, err := Solve(tt.p); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("got %v, want %q", err, tt.want)
+		}
+	}
+}
+
+func TestSolveMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	for round := 0; round < 300; round++ {
+		rows, cols := 1+rng.Intn(4), 1+rng.Intn(5)
+		p := Problem{A: make([][]int, rows), B: make([]int, rows), Cost: make([]int, cols), Upper: make([]int, cols)}
+		for j := range p.Upper {
+			p.Upper[j] = rng.Intn(5)
+			p.Cost[j] = rng.Intn(4)
+		}
+		// Build B from a hidden solution half the time so most rounds are
+		// feasible
+		hidden := make([]int, cols)
+		for j := range hidden {
+			hidden[j] = rng.Intn(p.Upper[j] + 1)
+		}
+		for i := range p.A {
+			p.A[i] = make([]int, cols)
+			for j := range p.A[i] {
+				p.A[i][j] = rng.Intn(5) - 1
+				p.B[i] += p.A[i][j] * hidden[j]
+			}
+			if rng.Intn(2) == 0 {
+				p.B[i] += rng.Intn(3) - 1
+			}
+		}
+
+		want, found := bruteForce(p)
+		sol, err := Solve(p)
+		switch {
+		case !found && !errors.Is(err, ErrInfeasible):
+			t.Fatalf("round %d: %+v: got %v, want ErrInfeasible", round, p, err)
+		case found && err != nil:
+			t.Fatalf("round %d: %+v: %v", round, p, err)
+		case found && sol.Cost != want:
+			t.Fatalf("round %d: %+v: cost %d, want %d", round, p, sol.Cost, want)
+		}
+		if found {
+			checkSolution(t, p, sol)
+		}
+	}
+}
+
+func bruteForce(p Problem) (int, bool) {
+	x := make([]int, len(p.Upper))
+	best, found := 0, false
+	var try func(j int)
+	try = func(j int) {
+		if j < len(x) {
+			for x[j] = 0; x[j] <= p.Upper[j]; x[j]++ {
+				try(j + 1)
+			}
+			return
+		}
+		for i, row := range p.A {
+			sum := 0
+			for k, a := range row {
+				sum += a * x[k]
+			}
+			if sum != p.B[i] {
+				return
+			}
+		}
+		cost := 0
+		for k, v := range x {
+			cost += p.Cost[k] * v
+		}
+		if !found || cost < best {
+			best, found = cost, true
+		}
+	}
+	try(0)
+	return best, found
+}
+
+func checkSolution(t *testing.T, p Problem, sol Solution) {
+	t.Helper()
+	for i, row := range p.A {
+		sum := 0
+		for j, a := range row {
+			sum += a * sol.X[j]
+		}
+		if sum != p.B[i] {
+			t.Errorf("row %d sums to %d, want %d (x = %v)", i, sum, p.B[i], sol.X)
+		}
+	}
+	cost := 0
+	for j, v := range sol.X {
+		if v < 0 || (p.Upper != nil && p.Upper[j] >= 0 && v > p.Upper[j]) {
+			t.Errorf("x[%d] = %d out of bounds", j, v)
+		}
+		c := 1
+		if p.Cost != nil {
+			c = p.Cost[j]
+		}
+		cost += c * v
+	}
+	if cost != sol.Cost {
+		t.Errorf("reported cost %d, x costs %d", sol.Cost, cost)
+	}
+}
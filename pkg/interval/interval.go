@@ -0,0 +1,248 @@
+// Package interval keeps sets of ranges as sorted, disjoint lists, for
+// merging ranges and asking what a range covers with a binary search.
+//
+// Set is for continuous values: a range runs from Lo to Hi and two ranges
+// that touch at an endpoint merge into one. Sets are taken up to their
+// endpoints, so ranges of zero length are dropped and subtracting [2, 3]
+// from [1, 5] leaves [1, 2] and [3, 5]. Ints is for whole tiles: a range
+// holds Lo to Hi inclusive, neighbours like [1, 3] and [4, 5] merge, and
+// subtracting is exact.
+package interval
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// Range runs from Lo to Hi
+type Range[T cmp.Ordered] struct {
+	Lo, Hi T
+}
+
+// Set is a union of ranges. The zero value is empty and ready to use.
+type Set[T cmp.Ordered] struct {
+	ranges []Range[T] // sorted, Lo < Hi, with gaps between them
+}
+
+// Of returns the set holding the given ranges
+func Of[T cmp.Ordered](ranges ...Range[T]) *Set[T] {
+	s := &Set[T]{}
+	for _, r := range ranges {
+		s.Insert(r.Lo, r.Hi)
+	}
+	return s
+}
+
+// Len is the number of disjoint ranges
+func (s *Set[T]) Len() int {
+	return len(s.ranges)
+}
+
+// All yields the ranges in increasing order
+func (s *Set[T]) All() iter.Seq[Range[T]] {
+	return func(yield func(Range[T]) bool) {
+		for _, r := range s.ranges {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
+// Ranges returns a copy of the ranges in increasing order
+func (s *Set[T]) Ranges() []Range[T] {
+	return slices.Clone(s.ranges)
+}
+
+// Clone returns an independent copy of s
+func (s *Set[T]) Clone() *Set[T] {
+	return &Set[T]{ranges: slices.Clone(s.ranges)}
+}
+
+// span finds the ranges from i up to j that touch [lo, hi]
+func (s *Set[T]) span(lo, hi T) (i, j int) {
+	i = sort.Search(len(s.ranges), func(k int) bool { return s.ranges[k].Hi >= lo })
+	j = sort.Search(len(s.ranges), func(k int) bool { return s.ranges[k].Lo > hi })
+	return i, j
+}
+
+// Insert adds [lo, hi], merging it with every range it touches
+func (s *Set[T]) Insert(lo, hi T) {
+	if lo >= hi {
+		return
+	}
+	i, j := s.span(lo, hi)
+	if i < j {
+		lo = min(lo, s.ranges[i].Lo)
+		hi = max(hi, s.ranges[j-1].Hi)
+	}
+	s.ranges = slices.Replace(s.ranges, i, j, Range[T]{lo, hi})
+}
+
+// Remove takes [lo, hi] out of the set
+func (s *Set[T]) Remove(lo, hi T) {
+	if lo >= hi {
+		return
+	}
+	i, j := s.span(lo, hi)
+	var keep []Range[T]
+	if i < j && s.ranges[i].Lo < lo {
+		keep = append(keep, Range[T]{s.ranges[i].Lo, lo})
+	}
+	if i < j && s.ranges[j-1].Hi > hi {
+		keep = append(keep, Range[T]{hi, s.ranges[j-1].Hi})
+	}
+	s.ranges = slices.Replace(s.ranges, i, j, keep...)
+}
+
+// Contains reports whether x lies in a range, endpoints included
+func (s *Set[T]) Contains(x T) bool {
+	return s.ContainsRange(x, x)
+}
+
+// ContainsRange reports whether one range holds all of [lo, hi]
+func (s *Set[T]) ContainsRange(lo, hi T) bool {
+	i := sort.Search(len(s.ranges), func(k int) bool { return s.ranges[k].Hi >= hi })
+	return i < len(s.ranges) && s.ranges[i].Lo <= lo
+}
+
+// Union is every value in s or o
+func (s *Set[T]) Union(o *Set[T]) *Set[T] {
+	out := s.Clone()
+	for _, r := range o.ranges {
+		out.Insert(r.Lo, r.Hi)
+	}
+	return out
+}
+
+// Subtract is every value in s but not in o
+func (s *Set[T]) Subtract(o *Set[T]) *Set[T] {
+	out := s.Clone()
+	for _, r := range o.ranges {
+		out.Remove(r.Lo, r.Hi)
+	}
+	return out
+}
+
+// Intersect is every value in both s and o
+func (s *Set[T]) Intersect(o *Set[T]) *Set[T] {
+	out := &Set[T]{}
+	a, b := s.ranges, o.ranges
+	for len(a) > 0 && len(b) > 0 {
+		lo, hi := max(a[0].Lo, b[0].Lo), min(a[0].Hi, b[0].Hi)
+		if lo < hi {
+			out.ranges = append(out.ranges, Range[T]{lo, hi})
+		}
+		if a[0].Hi < b[0].Hi {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+	return out
+}
+
+// Number is the types Total can add up
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Total is the summed length of the ranges of s
+func Total[T Number](s *Set[T]) T {
+	var total T
+	for _, r := range s.ranges {
+		total += r.Hi - r.Lo
+	}
+	return total
+}
+
+// Ints is a set of whole numbers kept as inclusive ranges. The zero value
+// is empty and ready to use.
+type Ints struct {
+	// Tile x is the range [x, x+1] of set
+	set Set[int]
+}
+
+// IntsOf returns the set holding the given inclusive ranges
+func IntsOf(ranges ...Range[int]) *Ints {
+	s := &Ints{}
+	for _, r := range ranges {
+		s.Insert(r.Lo, r.Hi)
+	}
+	return s
+}
+
+// Len is the number of disjoint ranges
+func (s *Ints) Len() int {
+	return s.set.Len()
+}
+
+// All yields the inclusive ranges in increasing order
+func (s *Ints) All() iter.Seq[Range[int]] {
+	return func(yield func(Range[int]) bool) {
+		for r := range s.set.All() {
+			if !yield(Range[int]{r.Lo, r.Hi - 1}) {
+				return
+			}
+		}
+	}
+}
+
+// Ranges returns the inclusive ranges in increasing order
+func (s *Ints) Ranges() []Range[int] {
+	return slices.Collect(s.All())
+}
+
+// Insert adds lo to hi inclusive; nothing when hi < lo
+func (s *Ints) Insert(lo, hi int) {
+	s.set.Insert(lo, hi+1)
+}
+
+// Remove takes lo to hi inclusive out of the set
+func (s *Ints) Remove(lo, hi int) {
+	s.set.Remove(lo, hi+1)
+}
+
+// Contains reports whether x is in the set
+func (s *Ints) Contains(x int) bool {
+	return s.set.ContainsRange(x, x+1)
+}
+
+// ContainsRange reports whether every number from lo to hi is in the set
+func (s *Ints) ContainsRange(lo, hi int) bool {
+	return s.set.ContainsRange(lo, hi+1)
+}
+
+// RangeOf returns the range holding x; false when x is not in the set
+func (s *Ints) RangeOf(x int) (Range[int], bool) {
+	rs := s.set.ranges
+	i := sort.Search(len(rs), func(k int) bool { return rs[k].Hi > x })
+	if i == len(rs) || rs[i].Lo > x {
+		return Range[int]{}, false
+	}
+	return Range[int]{rs[i].Lo, rs[i].Hi - 1}, true
+}
+
+// Total counts the numbers in the set
+func (s *Ints) Total() int {
+	return Total(&s.set)
+}
+
+// Union is every number in s or o
+func (s *Ints) Union(o *Ints) *Ints {
+	return &Ints{set: *s.set.Union(&o.set)}
+}
+
+// Subtract is every number in s but not in o
+func (s *Ints) Subtract(o *Ints) *Ints {
+	return &Ints{set: *s.set.Subtract(&o.set)}
+}
+
+// Intersect is every number in both s and o
+func (s *Ints) Intersect(o *Ints) *Ints {
+	return &Ints{set: *s.set.Intersect(&o.set)}
+}
@@ -0,0 +1,176 @@
+package interval
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSetInsert(t *testing.T) {
+	s := &Set[float64]{}
+	s.Insert(5, 7)
+	s.Insert(1, 2)
+	s.Insert(2, 3)     // touches 1-2
+	s.Insert(6, 6)     // empty, ignored
+	s.Insert(9, 8)     // backwards, ignored
+	s.Insert(10, 11.5) // apart
+	want := []Range[float64]{{1, 3}, {5, 7}, {10, 11.5}}
+	if got := s.Ranges(); !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	s.Insert(2.5, 10)
+	if got := s.Ranges(); !slices.Equal(got, []Range[float64]{{1, 11.5}}) {
+		t.Errorf("bridging insert gave %v", got)
+	}
+	if Total(s) != 10.5 {
+		t.Errorf("Total = %v", Total(s))
+	}
+}
+
+func TestSetContains(t *testing.T) {
+	s := Of(Range[int]{0, 4}, Range[int]{8, 12})
+	tests := []struct {
+		lo, hi int
+		want   bool
+	}{
+		{0, 4, true},
+		{4, 4, true}, // endpoints are in
+		{1, 3, true},
+		{3, 9, false}, // spans the gap
+		{5, 6, false},
+		{8, 12, true},
+		{12, 13, false},
+		{-1, 0, false},
+	}
+	for _, tt := range tests {
+		if got := s.ContainsRange(tt.lo, tt.hi); got != tt.want {
+			t.Errorf("ContainsRange(%d, %d) = %v, want %v", tt.lo, tt.hi, got, tt.want)
+		}
+	}
+	if !s.Contains(8) || s.Contains(6) {
+		t.Error("Contains wrong")
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a := Of(Range[int]{0, 10}, Range[int]{20, 30})
+	b := Of(Range[int]{5, 25})
+	tests := []struct {
+		name string
+		got  *Set[int]
+		want []Range[int]
+	}{
+		{"union", a.Union(b), []Range[int]{{0, 30}}},
+		{"intersect", a.Intersect(b), []Range[int]{{5, 10}, {20, 25}}},
+		{"subtract", a.Subtract(b), []Range[int]{{0, 5}, {25, 30}}},
+		{"subtract the other way", b.Subtract(a), []Range[int]{{10, 20}}},
+		{"touching intersect is empty", Of(Range[int]{0, 2}).Intersect(Of(Range[int]{2, 4})), nil},
+	}
+	for _, tt := range tests {
+		if got := tt.got.Ranges(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := a.Ranges(); !slices.Equal(got, []Range[int]{{0, 10}, {20, 30}}) {
+		t.Errorf("operations changed a: %v", got)
+	}
+}
+
+func TestSetStrings(t *testing.T) {
+	s := Of(Range[string]{"apple", "cherry"}, Range[string]{"banana", "date"})
+	if s.Len() != 1 || !s.Contains("coconut") || s.Contains("fig") {
+		t.Errorf("got %v", s.Ranges())
+	}
+}
+
+func TestIntsMergeNeighbours(t *testing.T) {
+	s := IntsOf(Range[int]{1, 3}, Range[int]{4, 5}, Range[int]{8, 8})
+	if got := s.Ranges(); !slices.Equal(got, []Range[int]{{1, 5}, {8, 8}}) {
+		t.Errorf("got %v", got)
+	}
+	if s.Total() != 6 {
+		t.Errorf("Total = %d", s.Total())
+	}
+	s.Remove(2, 3)
+	if got := s.Ranges(); !slices.Equal(got, []Range[int]{{1, 1}, {4, 5}, {8, 8}}) {
+		t.Errorf("after Remove got %v", got)
+	}
+	if !s.ContainsRange(4, 5) || s.ContainsRange(1, 4) || !s.Contains(8) || s.Contains(7) {
+		t.Error("containment wrong")
+	}
+	if got := IntsOf(Range[int]{1, 3}).Intersect(IntsOf(Range[int]{3, 5})).Ranges(); !slices.Equal(got, []Range[int]{{3, 3}}) {
+		t.Errorf("shared tile lost: %v", got)
+	}
+}
+
+// TestIntsMatchTiles checks Ints against a plain array of tiles
+func TestIntsMatchTiles(t *testing.T) {
+	const size = 40
+	rng := rand.New(rand.NewSource(48))
+	for round := 0; round < 200; round++ {
+		s, o := &Ints{}, &Ints{}
+		var tiles, other [size]bool
+		for op := 0; op < 8; op++ {
+			lo := rng.Intn(size)
+			hi := min(size-1, lo+rng.Intn(8))
+			switch rng.Intn(3) {
+			case 0, 1:
+				s.Insert(lo, hi)
+				for x := lo; x <= hi; x++ {
+					tiles[x] = true
+				}
+			default:
+				s.Remove(lo, hi)
+				for x := lo; x <= hi; x++ {
+					tiles[x] = false
+				}
+			}
+			lo = rng.Intn(size)
+			hi = min(size-1, lo+rng.Intn(8))
+			o.Insert(lo, hi)
+			for x := lo; x <= hi; x++ {
+				other[x] = true
+			}
+		}
+
+		check := func(name string, got *Ints, want func(x int) bool) {
+			count := 0
+			for x := 0; x < size; x++ {
+				if got.Contains(x) != want(x) {
+					t.Fatalf("round %d %s: tile %d is %v, want %v (%v)", round, name, x, got.Contains(x), want(x), got.Ranges())
+				}
+				r, ok := got.RangeOf(x)
+				if ok != want(x) || ok && (r.Lo > x || r.Hi < x || !got.ContainsRange(r.Lo, r.Hi) || got.Contains(r.Lo-1) || got.Contains(r.Hi+1)) {
+					t.Fatalf("round %d %s: RangeOf(%d) = %v, %v (%v)", round, name, x, r, ok, got.Ranges())
+				}
+				if want(x) {
+					count++
+				}
+			}
+			if got.Total() != count {
+				t.Fatalf("round %d %s: Total %d, want %d", round, name, got.Total(), count)
+			}
+			prev := -2
+			for _, r := range got.Ranges() {
+				if r.Lo <= prev+1 || r.Hi < r.Lo {
+					t.Fatalf("round %d %s: ranges %v not merged", round, name, got.Ranges())
+				}
+				prev = r.Hi
+			}
+		}
+		check("set", s, func(x int) bool { return tiles[x] })
+		check("union", s.Union(o), func(x int) bool { return tiles[x] || other[x] })
+		check("intersect", s.Intersect(o), func(x int) bool { return tiles[x] && other[x] })
+		check("subtract", s.Subtract(o), func(x int) bool { return tiles[x] && !other[x] })
+
+		lo := rng.Intn(size)
+		hi := min(size-1, lo+rng.Intn(6))
+		want := true
+		for x := lo; x <= hi; x++ {
+			want = want && tiles[x]
+		}
+		if got := s.ContainsRange(lo, hi); got != want {
+			t.Fatalf("round %d: ContainsRange(%d, %d) = %v, want %v (%v)", round, lo, hi, got, want, s.Ranges())
+		}
+	}
+}
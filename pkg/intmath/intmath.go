@@ -0,0 +1,214 @@
+// Package intmath is number theory on Go's integer types: gcd and lcm,
+// division and remainder that round the way puzzles want, modular inverse
+// and powers, the Chinese remainder theorem and integer square roots.
+package intmath
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// Signed is any signed integer type
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Integer is any integer type
+type Integer interface {
+	Signed | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Abs is the absolute value of x
+func Abs[T Signed](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// GCD is the greatest common divisor of a and b, never negative;
+// GCD(0, 0) is 0
+func GCD[T Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// LCM is the least common multiple of a and b, never negative; it is 0
+// when either is 0
+func LCM[T Integer](a, b T) T {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	l := a / GCD(a, b) * b
+	if l < 0 {
+		return -l
+	}
+	return l
+}
+
+// Mod is the Euclidean remainder of a by m: always in [0, |m|), unlike
+// Go's %, which takes the sign of a
+func Mod[T Integer](a, m T) T {
+	r := a % m
+	if r < 0 {
+		if m < 0 {
+			return r - m
+		}
+		return r + m
+	}
+	return r
+}
+
+// FloorDiv is a / b rounded towards negative infinity, unlike Go's /,
+// which rounds towards zero
+func FloorDiv[T Integer](a, b T) T {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// ExtGCD returns g = GCD(a, b) and x, y with a*x + b*y = g
+func ExtGCD[T Signed](a, b T) (g, x, y T) {
+	x0, x1, y0, y1 := T(1), T(0), T(0), T(1)
+	for b != 0 {
+		q := a / b
+		a, b = b, a-q*b
+		x0, x1 = x1, x0-q*x1
+		y0, y1 = y1, y0-q*y1
+	}
+	if a < 0 {
+		return -a, -x0, -y0
+	}
+	return a, x0, y0
+}
+
+// ErrNotInvertible is returned by ModInverse when a and m share a factor
+var ErrNotInvertible = errors.New("intmath: not invertible")
+
+// ModInverse returns x in [0, m) with a*x = 1 mod m, for m > 0
+func ModInverse[T Signed](a, m T) (T, error) {
+	g, x, _ := ExtGCD(Mod(a, m), m)
+	if g != 1 {
+		return 0, fmt.Errorf("%w: %d mod %d", ErrNotInvertible, a, m)
+	}
+	return Mod(x, m), nil
+}
+
+// MulMod is a*b mod m in [0, m) for m > 0, without overflowing however
+// large a and b are
+func MulMod(a, b, m int64) int64 {
+	hi, lo := bits.Mul64(uint64(Mod(a, m)), uint64(Mod(b, m)))
+	return int64(bits.Rem64(hi, lo, uint64(m)))
+}
+
+// PowMod is base^exp mod m in [0, m) for exp >= 0 and m > 0, by repeated
+// squaring
+func PowMod(base, exp, m int64) int64 {
+	if exp < 0 {
+		panic(fmt.Sprintf("intmath: negative exponent %d", exp))
+	}
+	result := Mod(1, m)
+	base = Mod(base, m)
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = MulMod(result, base, m)
+		}
+		base = MulMod(base, base, m)
+	}
+	return result
+}
+
+var (
+	// ErrNoSolution is returned by CRT when the congruences contradict
+	// each other
+	ErrNoSolution = errors.New("intmath: congruences have no common solution")
+	// ErrOverflow is returned by CRT when the combined modulus does not fit
+	// an int64; CRTBig has no such limit
+	ErrOverflow = errors.New("intmath: overflows int64")
+)
+
+// CRT solves x = residues[i] mod moduli[i] for every i, returning the
+// smallest x >= 0 and the combined modulus, the lcm of the moduli. The
+// moduli must be positive and need not be coprime.
+func CRT(residues, moduli []int64) (x, m int64, err error) {
+	if len(residues) != len(moduli) {
+		return 0, 0, fmt.Errorf("intmath: %d residues for %d moduli", len(residues), len(moduli))
+	}
+	x, m = 0, 1
+	for i, mi := range moduli {
+		if mi <= 0 {
+			return 0, 0, fmt.Errorf("intmath: modulus %d is not positive", mi)
+		}
+		ri := Mod(residues[i], mi)
+		g := GCD(m, mi)
+		diff := ri - x%mi // both in (-mi, mi)
+		if diff%g != 0 {
+			return 0, 0, fmt.Errorf("%w: x = %d mod %d and x = %d mod %d", ErrNoSolution, x, m, residues[i], mi)
+		}
+		step := mi / g
+		hi, l := bits.Mul64(uint64(m), uint64(step))
+		if hi != 0 || l > math.MaxInt64 {
+			return 0, 0, fmt.Errorf("%w: lcm of %d and %d", ErrOverflow, m, mi)
+		}
+		// x + m*t = ri mod mi, so t = (diff/g) * (m/g)^-1 mod step
+		inv, _ := ModInverse(m/g, step)
+		t := MulMod(diff/g, inv, step)
+		x += m * t // m*t < m*step fits, and so does the sum
+		m = int64(l)
+	}
+	return Mod(x, m), m, nil
+}
+
+// CRTBig is CRT on arbitrarily large numbers
+func CRTBig(residues, moduli []*big.Int) (x, m *big.Int, err error) {
+	if len(residues) != len(moduli) {
+		return nil, nil, fmt.Errorf("intmath: %d residues for %d moduli", len(residues), len(moduli))
+	}
+	x, m = big.NewInt(0), big.NewInt(1)
+	for i, mi := range moduli {
+		if mi.Sign() <= 0 {
+			return nil, nil, fmt.Errorf("intmath: modulus %v is not positive", mi)
+		}
+		ri := new(big.Int).Mod(residues[i], mi)
+		g, inv := new(big.Int), new(big.Int)
+		g.GCD(inv, nil, m, mi) // inv*m = g mod mi
+		diff := new(big.Int).Sub(ri, x)
+		if new(big.Int).Mod(diff, g).Sign() != 0 {
+			return nil, nil, fmt.Errorf("%w: x = %v mod %v and x = %v mod %v", ErrNoSolution, x, m, residues[i], mi)
+		}
+		step := new(big.Int).Quo(mi, g)
+		t := diff.Quo(diff, g)
+		t.Mul(t, inv).Mod(t, step)
+		x.Add(x, t.Mul(t, m))
+		m.Mul(m, step)
+		x.Mod(x, m)
+	}
+	return x, m, nil
+}
+
+// ISqrt is the largest r with r*r <= n, for n >= 0
+func ISqrt[T Integer](n T) T {
+	if n < 0 {
+		panic(fmt.Sprintf("intmath: square root of %d", n))
+	}
+	u := uint64(n)
+	r := uint64(math.Sqrt(float64(u)))
+	// The float estimate can be a little off either way for large n
+	for r > 0 && (r > math.MaxUint32 || r*r > u) {
+		r--
+	}
+	for r+1 <= math.MaxUint32 && (r+1)*(r+1) <= u {
+		r++
+	}
+	return T(r)
+}
@@ -0,0 +1,199 @@
+package intmath
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestGCDAndLCM(t *testing.T) {
+	tests := []struct {
+		a, b, gcd, lcm int
+	}{
+		{12, 18, 6, 36},
+		{-12, 18, 6, 36},
+		{12, -18, 6, 36},
+		{7, 13, 1, 91},
+		{0, 5, 5, 0},
+		{0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := GCD(tt.a, tt.b); got != tt.gcd {
+			t.Errorf("GCD(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.gcd)
+		}
+		if got := LCM(tt.a, tt.b); got != tt.lcm {
+			t.Errorf("LCM(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.lcm)
+		}
+	}
+	if GCD[uint8](200, 150) != 50 {
+		t.Error("GCD on uint8")
+	}
+}
+
+func TestModAndFloorDiv(t *testing.T) {
+	tests := []struct {
+		a, m, mod, div int
+	}{
+		{7, 3, 1, 2},
+		{-7, 3, 2, -3},
+		{7, -3, 1, -3},
+		{-7, -3, 2, 2},
+		{-6, 3, 0, -2},
+		{0, 5, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := Mod(tt.a, tt.m); got != tt.mod {
+			t.Errorf("Mod(%d, %d) = %d, want %d", tt.a, tt.m, got, tt.mod)
+		}
+		if got := FloorDiv(tt.a, tt.m); got != tt.div {
+			t.Errorf("FloorDiv(%d, %d) = %d, want %d", tt.a, tt.m, got, tt.div)
+		}
+	}
+}
+
+func TestModInverse(t *testing.T) {
+	for m := int64(2); m < 60; m++ {
+		for a := -m; a < 2*m; a++ {
+			inv, err := ModInverse(a, m)
+			if GCD(a, m) != 1 {
+				if !errors.Is(err, ErrNotInvertible) {
+					t.Fatalf("ModInverse(%d, %d) = %d, %v; want ErrNotInvertible", a, m, inv, err)
+				}
+				continue
+			}
+			if err != nil || inv < 0 || inv >= m || Mod(a*inv, m) != 1 {
+				t.Fatalf("ModInverse(%d, %d) = %d, %v", a, m, inv, err)
+			}
+		}
+	}
+}
+
+func TestPowMod(t *testing.T) {
+	tests := []struct {
+		base, exp, m, want int64
+	}{
+		{2, 10, 1000, 24},
+		{-2, 3, 7, 6},
+		{5, 0, 7, 1},
+		{5, 0, 1, 0},
+		{3, 200, 1_000_000_007, 136_318_165},
+		// Squares near 2^62 overflow a plain multiplication
+		{math.MaxInt64 - 1, 2, math.MaxInt64, 1},
+	}
+	for _, tt := range tests {
+		if got := PowMod(tt.base, tt.exp, tt.m); got != tt.want {
+			t.Errorf("PowMod(%d, %d, %d) = %d, want %d", tt.base, tt.exp, tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestCRT(t *testing.T) {
+	tests := []struct {
+		name             string
+		residues, moduli []int64
+		x, m             int64
+	}{
+		{"coprime", []int64{2, 3, 2}, []int64{3, 5, 7}, 23, 105},
+		{"shared factor", []int64{3, 7}, []int64{4, 6}, 7, 12},
+		{"negative residue", []int64{-1, 0}, []int64{5, 3}, 9, 15},
+		{"none", nil, nil, 0, 1},
+		// 2020 day 13: buses 17,x,13,19 leave at t, t+2, t+3
+		{"bus schedule", []int64{0, -2, -3}, []int64{17, 13, 19}, 3417, 4199},
+	}
+	for _, tt := range tests {
+		x, m, err := CRT(tt.residues, tt.moduli)
+		if err != nil || x != tt.x || m != tt.m {
+			t.Errorf("%s: got %d mod %d, %v; want %d mod %d", tt.name, x, m, err, tt.x, tt.m)
+		}
+	}
+
+	if _, _, err := CRT([]int64{1, 2}, []int64{4, 6}); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("contradiction: got %v", err)
+	}
+	large := []int64{1_000_000_007, 998_244_353, 1_000_000_009}
+	if _, _, err := CRT([]int64{1, 2, 3}, large); !errors.Is(err, ErrOverflow) {
+		t.Errorf("overflow: got %v", err)
+	}
+}
+
+// TestCRTBig checks CRTBig against CRT where both work, and that it carries
+// on where CRT overflows
+func TestCRTBig(t *testing.T) {
+	bigs := func(ns []int64) []*big.Int {
+		var out []*big.Int
+		for _, n := range ns {
+			out = append(out, big.NewInt(n))
+		}
+		return out
+	}
+	rng := rand.New(rand.NewSource(50))
+	for round := 0; round < 300; round++ {
+		n := 1 + rng.Intn(4)
+		residues, moduli := make([]int64, n), make([]int64, n)
+		for i := range moduli {
+			moduli[i] = 1 + rng.Int63n(60)
+			residues[i] = rng.Int63n(200) - 100
+		}
+		x, m, err := CRT(residues, moduli)
+		bx, bm, berr := CRTBig(bigs(residues), bigs(moduli))
+		if (err == nil) != (berr == nil) {
+			t.Fatalf("%v mod %v: CRT says %v, CRTBig says %v", residues, moduli, err, berr)
+		}
+		if err != nil {
+			continue
+		}
+		if bx.Int64() != x || bm.Int64() != m {
+			t.Fatalf("%v mod %v: CRT %d mod %d, CRTBig %v mod %v", residues, moduli, x, m, bx, bm)
+		}
+		for i, mi := range moduli {
+			if Mod(x-residues[i], mi) != 0 {
+				t.Fatalf("%v mod %v: %d misses congruence %d", residues, moduli, x, i)
+			}
+		}
+	}
+
+	moduli := []int64{1_000_000_007, 998_244_353, 1_000_000_009}
+	residues := []int64{1, 2, 3}
+	x, m, err := CRTBig(bigs(residues), bigs(moduli))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := new(big.Int).Mul(big.NewInt(moduli[0]), big.NewInt(moduli[1]))
+	want.Mul(want, big.NewInt(moduli[2]))
+	if m.Cmp(want) != 0 {
+		t.Errorf("modulus %v, want %v", m, want)
+	}
+	for i, mi := range moduli {
+		if new(big.Int).Mod(x, big.NewInt(mi)).Int64() != residues[i] {
+			t.Errorf("%v misses congruence %d", x, i)
+		}
+	}
+}
+
+func TestISqrt(t *testing.T) {
+	for n := 0; n < 10000; n++ {
+		r := ISqrt(n)
+		if r*r > n || (r+1)*(r+1) <= n {
+			t.Fatalf("ISqrt(%d) = %d", n, r)
+		}
+	}
+	tests := []struct {
+		n, want uint64
+	}{
+		{math.MaxUint64, math.MaxUint32},
+		{(1<<32 - 1) * (1<<32 - 1), 1<<32 - 1},
+		{(1<<32-1)*(1<<32-1) - 1, 1<<32 - 2},
+		{1 << 62, 1 << 31},
+		{1<<62 - 1, 1<<31 - 1},
+	}
+	for _, tt := range tests {
+		if got := ISqrt(tt.n); got != tt.want {
+			t.Errorf("ISqrt(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+	if got := ISqrt(int64(math.MaxInt64)); got != 3037000499 {
+		t.Errorf("ISqrt(MaxInt64) = %d", got)
+	}
+}
@@ -0,0 +1,289 @@
+// Package parse reads the shapes puzzle input usually comes in: numbers
+// scattered through a line, lines that follow a fixed wording, blocks
+// separated by blank lines and key:value fields. Errors say where the input
+// went wrong, as "line N:" and, within a line, "column N:".
+package parse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Ints returns every signed integer in s in order. A '-' or '+' right
+// before a digit is its sign, so "x=-3..+4" gives -3 and 4; anything else
+// separates numbers.
+func Ints(s string) ([]int, error) {
+	var ints []int
+	for i := 0; i < len(s); {
+		start := i
+		if (s[i] == '-' || s[i] == '+') && i+1 < len(s) && isDigit(s[i+1]) {
+			i++
+		}
+		if !isDigit(s[i]) {
+			i = start + 1
+			continue
+		}
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		n, err := strconv.Atoi(s[start:i])
+		if err != nil {
+			return nil, fmt.Errorf("column %d: %w", start+1, err)
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Lines calls each for every line of r with its line number from 1; an
+// error from each stops the scan and comes back with the line number
+func Lines(r io.Reader, each func(lineNo int, line string) error) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if err := each(lineNo, scanner.Text()); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// ReadLines is Lines on a file; errors also name the file
+func ReadLines(filename string, each func(lineNo int, line string) error) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := Lines(f, each); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
+
+// Section is a block of lines without blank lines in between; Line is the
+// line number of its first line
+type Section struct {
+	Line  int
+	Lines []string
+}
+
+// Sections splits lines into the blocks between blank lines. A line of
+// only spaces counts as blank, and runs of blank lines separate just once.
+func Sections(lines []string) []Section {
+	var sections []Section
+	var cur *Section
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			sections = append(sections, Section{Line: i + 1})
+			cur = &sections[len(sections)-1]
+		}
+		cur.Lines = append(cur.Lines, line)
+	}
+	return sections
+}
+
+// ReadSections is Sections on the lines of r
+func ReadSections(r io.Reader) ([]Section, error) {
+	var lines []string
+	err := Lines(r, func(_ int, line string) error {
+		lines = append(lines, line)
+		return nil
+	})
+	return Sections(lines), err
+}
+
+// Field is one key:value token
+type Field struct {
+	Key, Value string
+}
+
+// Fields splits s on spaces into key:value tokens, cutting each at its
+// first ':'. A token without ':' or with an empty key is an error; so is a
+// key seen twice.
+func Fields(s string) ([]Field, error) {
+	var fields []Field
+	seen := map[string]bool{}
+	for i := 0; i < len(s); {
+		if s[i] == ' ' || s[i] == '\t' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(s) && s[i] != ' ' && s[i] != '\t' {
+			i++
+		}
+		token := s[start:i]
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("column %d: want key:value, got %q", start+1, token)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("column %d: key %q repeated", start+1, key)
+		}
+		seen[key] = true
+		fields = append(fields, Field{key, value})
+	}
+	return fields, nil
+}
+
+// Template is a line wording with named holes, such as
+// "move {n:int} from {from:int} to {to:int}". A {name} hole takes the
+// shortest text that lets the next literal match, or the rest of the line
+// when it comes last; a {name:int} hole takes a signed integer.
+type Template struct {
+	Name  string
+	parts []part
+}
+
+// part is a literal, or a hole when name is set
+type part struct {
+	literal string
+	name    string
+	isInt   bool
+}
+
+// NewTemplate compiles pattern. Two holes must not touch: nothing would
+// tell where one stops.
+func NewTemplate(name, pattern string) (*Template, error) {
+	t := &Template{Name: name}
+	rest := pattern
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			t.parts = append(t.parts, part{literal: rest})
+			break
+		}
+		if open > 0 {
+			t.parts = append(t.parts, part{literal: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("template %s: unclosed {", name)
+		}
+		hole := rest[open+1 : open+end]
+		rest = rest[open+end+1:]
+
+		hname, kind, _ := strings.Cut(hole, ":")
+		if hname == "" || (kind != "" && kind != "int") {
+			return nil, fmt.Errorf("template %s: bad hole {%s}", name, hole)
+		}
+		if n := len(t.parts); n > 0 && t.parts[n-1].name != "" {
+			return nil, fmt.Errorf("template %s: holes {%s} and {%s} touch", name, t.parts[n-1].name, hname)
+		}
+		t.parts = append(t.parts, part{name: hname, isInt: kind == "int"})
+	}
+	return t, nil
+}
+
+// MustTemplate is NewTemplate for patterns fixed in the source
+func MustTemplate(name, pattern string) *Template {
+	t, err := NewTemplate(name, pattern)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Match is the holes of a matched line by name
+type Match struct {
+	Template string
+	values   map[string]string
+	ints     map[string]int
+}
+
+// String is the text in hole name
+func (m Match) String(name string) string {
+	return m.values[name]
+}
+
+// Int is the number in {name:int}
+func (m Match) Int(name string) int {
+	return m.ints[name]
+}
+
+// Match fills the holes of t from line, or says at which column and why
+// line does not fit
+func (t *Template) Match(line string) (Match, error) {
+	m := Match{Template: t.Name, values: map[string]string{}, ints: map[string]int{}}
+	pos := 0
+	fail := func(format string, args ...any) (Match, error) {
+		return Match{}, fmt.Errorf("template %s: column %d: %s", t.Name, pos+1, fmt.Sprintf(format, args...))
+	}
+	for i, p := range t.parts {
+		rest := line[pos:]
+		switch {
+		case p.name == "":
+			if !strings.HasPrefix(rest, p.literal) {
+				return fail("want %q, got %q", p.literal, rest)
+			}
+			pos += len(p.literal)
+		case p.isInt:
+			n := 0
+			if n < len(rest) && (rest[n] == '-' || rest[n] == '+') {
+				n++
+			}
+			digits := n
+			for n < len(rest) && isDigit(rest[n]) {
+				n++
+			}
+			if n == digits {
+				return fail("want a number for {%s}, got %q", p.name, rest)
+			}
+			v, err := strconv.Atoi(rest[:n])
+			if err != nil {
+				return fail("{%s}: %v", p.name, err)
+			}
+			m.values[p.name], m.ints[p.name] = rest[:n], v
+			pos += n
+		default:
+			n := len(rest)
+			if i+1 < len(t.parts) {
+				n = strings.Index(rest, t.parts[i+1].literal)
+				if n < 0 {
+					pos += len(rest)
+					return fail("want %q after {%s}", t.parts[i+1].literal, p.name)
+				}
+			}
+			if n == 0 {
+				return fail("{%s} is empty", p.name)
+			}
+			m.values[p.name] = rest[:n]
+			pos += n
+		}
+	}
+	if pos < len(line) {
+		return fail("unexpected %q", line[pos:])
+	}
+	return m, nil
+}
+
+// Matcher tries templates in order
+type Matcher []*Template
+
+// Match returns the first template that fits line. When none does, the
+// error lists why each one failed.
+func (ms Matcher) Match(line string) (Match, error) {
+	var why []string
+	for _, t := range ms {
+		m, err := t.Match(line)
+		if err == nil {
+			return m, nil
+		}
+		why = append(why, err.Error())
+	}
+	return Match{}, fmt.Errorf("no template fits %q: %s", line, strings.Join(why, "; "))
+}
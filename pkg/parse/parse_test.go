@@ -0,0 +1,167 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInts(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []int
+	}{
+		{"move 3 from 1 to 2", []int{3, 1, 2}},
+		{"x=-3..+4, y=10", []int{-3, 4, 10}},
+		{"7,1", []int{7, 1}},
+		{"p=0,4 v=3,-3", []int{0, 4, 3, -3}},
+		{"- 5 -", []int{5}},
+		{"5-3", []int{5, -3}},
+		{"no numbers", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := Ints(tt.in)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("Ints(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := Ints("ok 99999999999999999999999"); err == nil || !strings.HasPrefix(err.Error(), "column 4:") {
+		t.Errorf("overflow: %v", err)
+	}
+}
+
+func TestLines(t *testing.T) {
+	var seen []int
+	err := Lines(strings.NewReader("a\nb\nc\n"), func(lineNo int, line string) error {
+		seen = append(seen, lineNo)
+		if line == "b" {
+			_, err := MustTemplate("letter", "a").Match(line)
+			return err
+		}
+		return nil
+	})
+	if err == nil || err.Error() != `line 2: template letter: column 1: want "a", got "b"` {
+		t.Errorf("got %v", err)
+	}
+	if !slices.Equal(seen, []int{1, 2}) {
+		t.Errorf("kept going after the error: %v", seen)
+	}
+
+	filename := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(filename, []byte("1\nx\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	num := MustTemplate("number", "{n:int}")
+	err = ReadLines(filename, func(_ int, line string) error {
+		_, err := num.Match(line)
+		return err
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), filename+": line 2: template number: column 1:") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestSections(t *testing.T) {
+	lines := []string{"", "a", "b", "", "  ", "c", "", ""}
+	want := []Section{{Line: 2, Lines: []string{"a", "b"}}, {Line: 6, Lines: []string{"c"}}}
+	if got := Sections(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	got, err := ReadSections(strings.NewReader("x\n\ny\nz"))
+	if err != nil || len(got) != 2 || got[1].Line != 3 || !slices.Equal(got[1].Lines, []string{"y", "z"}) {
+		t.Errorf("ReadSections = %+v, %v", got, err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	got, err := Fields("ecl:gry pid:860033327  eyr:2020\thcl:#fffffd url:http://x")
+	want := []Field{{"ecl", "gry"}, {"pid", "860033327"}, {"eyr", "2020"}, {"hcl", "#fffffd"}, {"url", "http://x"}}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("got %v, %v", got, err)
+	}
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"byr:1937 iyr", `column 10: want key:value, got "iyr"`},
+		{":x", `column 1: want key:value`},
+		{"a:1 b:2 a:3", `column 9: key "a" repeated`},
+	}
+	for _, tt := range tests {
+		if _, err := Fields(tt.in); err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("Fields(%q) = %v, want %q", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	move := MustTemplate("move", "move {n:int} from {from:int} to {to:int}")
+	m, err := move.Match("move 13 from -2 to 9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Template != "move" || m.Int("n") != 13 || m.Int("from") != -2 || m.Int("to") != 9 || m.String("n") != "13" {
+		t.Errorf("got %+v", m)
+	}
+
+	rule := MustTemplate("rule", "{color} bags contain {contents}.")
+	m, err = rule.Match("light red bags contain 1 bright white bag, 2 muted yellow bags.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.String("color") != "light red" || m.String("contents") != "1 bright white bag, 2 muted yellow bags" {
+		t.Errorf("got %q and %q", m.String("color"), m.String("contents"))
+	}
+
+	tests := []struct {
+		template *Template
+		line     string
+		want     string
+	}{
+		{move, "move x from 1 to 2", `template move: column 6: want a number for {n}, got "x from 1 to 2"`},
+		{move, "move 1 to 2", `template move: column 7: want " from ", got " to 2"`},
+		{move, "move 1 from 2 to 3 now", `template move: column 19: unexpected " now"`},
+		{move, "move 99999999999999999999 from 1 to 2", `template move: column 6: {n}:`},
+		{rule, "light red bags contain nothing", `template rule: column 31: want "." after {contents}`},
+		{rule, " bags contain x.", `template rule: column 1: {color} is empty`},
+	}
+	for _, tt := range tests {
+		if _, err := tt.template.Match(tt.line); err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("Match(%q) = %v, want %q", tt.line, err, tt.want)
+		}
+	}
+}
+
+func TestNewTemplateErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"move {n", "unclosed {"},
+		{"{a}{b}", "holes {a} and {b} touch"},
+		{"{}", "bad hole {}"},
+		{"{n:float}", "bad hole {n:float}"},
+	}
+	for _, tt := range tests {
+		if _, err := NewTemplate("t", tt.pattern); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("NewTemplate(%q) = %v, want %q", tt.pattern, err, tt.want)
+		}
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	rotation := Matcher{MustTemplate("L", "L{n:int}"), MustTemplate("R", "R{n:int}")}
+	m, err := rotation.Match("R48")
+	if err != nil || m.Template != "R" || m.Int("n") != 48 {
+		t.Errorf("got %+v, %v", m, err)
+	}
+	_, err = rotation.Match("U7")
+	want := `no template fits "U7": template L: column 1: want "L", got "U7"; template R: column 1: want "R", got "U7"`
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v\nwant %s", err, want)
+	}
+}
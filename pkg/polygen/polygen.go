@@ -0,0 +1,420 @@
+// Package polygen generates random simple rectilinear polygons in the
+// vertex order used by 2025 day 09 puzzle inputs, for stress tests and
+// benchmarks.
+//
+// Shapes are grown as polyominoes on a small grid of cells, traced into a
+// boundary and then stretched onto random even coordinates. Keeping the
+// coordinates even means parallel edges are never a single tile apart, so
+// tile containment and continuous containment always agree. With
+// OneTileGaps set, some grid lines are packed a tile after the one before
+// instead, which gives the gaps a tile wide where the two differ.
+package polygen
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+
+	"aoc/pkg/geometry"
+)
+
+// Shape selects the kind of polygon to grow
+type Shape int
+
+const (
+	// Notched grows a random blob whose outline is full of notches
+	Notched Shape = iota
+	// Spiral winds a one cell wide corridor inwards
+	Spiral
+	// Corridor wanders a one cell wide snake of long straight runs
+	Corridor
+)
+
+func (s Shape) String() string {
+	switch s {
+	case Notched:
+		return "notched"
+	case Spiral:
+		return "spiral"
+	case Corridor:
+		return "corridor"
+	}
+	return fmt.Sprintf("Shape(%d)", int(s))
+}
+
+// ParseShape is the inverse of Shape.String
+func ParseShape(name string) (Shape, error) {
+	for _, s := range []Shape{Notched, Spiral, Corridor} {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("polygen: unknown shape %q", name)
+}
+
+// Options configures Generate. The same options always produce the same
+// polygon.
+type Options struct {
+	Seed     int64
+	Shape    Shape
+	Vertices int // minimum number of vertices, at least 4
+	MaxCoord int // coordinates fall in [0, MaxCoord]
+	// OneTileGaps puts about half the grid lines a single tile after the
+	// previous one, so some parallel edges are a tile apart
+	OneTileGaps bool
+}
+
+// ErrTooSmall is returned when MaxCoord cannot fit the requested vertices
+var ErrTooSmall = errors.New("polygen: coordinate range too small for vertex count")
+
+// Generate returns a simple rectilinear polygon with at least opts.Vertices
+// vertices (a few more when a shape cannot stop exactly), listed clockwise
+// on screen as the puzzle inputs are.
+func Generate(opts Options) ([]geometry.Point, error) {
+	if opts.Vertices < 4 {
+		opts.Vertices = 4
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	var s *cells
+	switch opts.Shape {
+	case Notched:
+		s = growNotched(rng, opts.Vertices)
+	case Spiral:
+		s = growSpiral(rng, opts.Vertices)
+	case Corridor:
+		s = growCorridor(rng, opts.Vertices)
+	default:
+		return nil, fmt.Errorf("polygen: unknown shape %v", opts.Shape)
+	}
+
+	outline := s.trace()
+	if len(outline) < opts.Vertices {
+		return nil, fmt.Errorf("polygen: %v shape stopped at %d of %d vertices", opts.Shape, len(outline), opts.Vertices)
+	}
+	points, err := stretch(rng, outline, opts.MaxCoord, opts.OneTileGaps)
+	if err != nil {
+		return nil, err
+	}
+	// Start somewhere other than the top left corner
+	start := rng.Intn(len(points))
+	return append(points[start:], points[:start]...), nil
+}
+
+// Write writes points in the puzzle input format, one "x,y" per line
+func Write(w io.Writer, points []geometry.Point) error {
+	bw := bufio.NewWriter(w)
+	for _, p := range points {
+		fmt.Fprintf(bw, "%d,%d\n", p.X, p.Y)
+	}
+	return bw.Flush()
+}
+
+// WriteFile writes points to filename in the puzzle input format
+func WriteFile(filename string, points []geometry.Point) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, points); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// cell is a unit square of the polyomino; its top left corner is the grid
+// point (X, Y) with y growing downwards
+type cell struct{ X, Y int }
+
+var (
+	sides = []cell{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	// ring lists the eight neighbours in order, each 4-adjacent to the next
+	ring = []cell{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+)
+
+// cells is a polyomino together with its current corner count
+type cells struct {
+	set     map[cell]bool
+	corners int
+}
+
+func newCells() *cells {
+	return &cells{set: map[cell]bool{}}
+}
+
+// isCorner reports whether grid point (x, y) is a polygon vertex, which is
+// when one or three of the four cells around it are filled
+func (s *cells) isCorner(x, y int) bool {
+	n := 0
+	for _, c := range []cell{{x - 1, y - 1}, {x, y - 1}, {x - 1, y}, {x, y}} {
+		if s.set[c] {
+			n++
+		}
+	}
+	return n == 1 || n == 3
+}
+
+func (s *cells) add(c cell) {
+	points := []cell{{c.X, c.Y}, {c.X + 1, c.Y}, {c.X, c.Y + 1}, {c.X + 1, c.Y + 1}}
+	for _, p := range points {
+		if s.isCorner(p.X, p.Y) {
+			s.corners--
+		}
+	}
+	s.set[c] = true
+	for _, p := range points {
+		if s.isCorner(p.X, p.Y) {
+			s.corners++
+		}
+	}
+}
+
+// canAdd reports whether filling the empty cell c keeps the outline a
+// single simple loop. The filled shape stays 4-connected as long as c
+// touches it, and the empty cells stay 4-connected (no holes, and so no
+// pinched corners) if the empty sides of c are joined through empty cells
+// of its ring. The test is local and so a little conservative.
+func (s *cells) canAdd(c cell) bool {
+	touches := false
+	for _, d := range sides {
+		if s.set[cell{c.X + d.X, c.Y + d.Y}] {
+			touches = true
+		}
+	}
+	if !touches {
+		return false
+	}
+
+	// Count the runs of empty ring cells that contain an empty side
+	runs := 0
+	start := -1
+	for i, d := range ring {
+		if s.set[cell{c.X + d.X, c.Y + d.Y}] {
+			start = i
+			break
+		}
+	}
+	inRun, runHasSide := false, false
+	for k := 1; k <= len(ring); k++ {
+		i := (start + k) % len(ring)
+		d := ring[i]
+		if s.set[cell{c.X + d.X, c.Y + d.Y}] {
+			if inRun && runHasSide {
+				runs++
+			}
+			inRun, runHasSide = false, false
+			continue
+		}
+		inRun = true
+		if i%2 == 0 {
+			runHasSide = true
+		}
+	}
+	return runs == 1
+}
+
+// growNotched grows a blob one random frontier cell at a time
+func growNotched(rng *rand.Rand, vertices int) *cells {
+	s := newCells()
+	s.add(cell{0, 0})
+	frontier := []cell{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	for s.corners < vertices && len(frontier) > 0 {
+		i := rng.Intn(len(frontier))
+		c := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+		if s.set[c] {
+			continue
+		}
+		if !s.canAdd(c) {
+			// It may become addable once its neighbours fill in
+			if rng.Intn(4) == 0 {
+				frontier = append(frontier, c)
+			}
+			continue
+		}
+		s.add(c)
+		for _, d := range sides {
+			if n := (cell{c.X + d.X, c.Y + d.Y}); !s.set[n] {
+				frontier = append(frontier, n)
+			}
+		}
+	}
+	return s
+}
+
+// growSpiral walks a corridor inwards around a w by h box, leaving a one
+// cell gap between the arms. A corridor of k straight runs has 2k+2
+// vertices.
+func growSpiral(rng *rand.Rand, vertices int) *cells {
+	runs := max(1, (vertices-1)/2)
+	w := runs + 2 + rng.Intn(runs/2+1)
+	h := runs + 2 + rng.Intn(runs/2+1)
+
+	s := newCells()
+	pos := cell{-1, 0}
+	lengths := []int{w, h - 1, w - 1}
+	for i := 0; i < runs; i++ {
+		if i >= len(lengths) {
+			shrink := 2 * ((i - 1) / 2)
+			if i%2 == 1 {
+				lengths = append(lengths, h-1-shrink)
+			} else {
+				lengths = append(lengths, w-1-shrink)
+			}
+		}
+		d := sides[(i+1)%4]
+		for j := 0; j < lengths[i]; j++ {
+			pos = cell{pos.X + d.X, pos.Y + d.Y}
+			s.add(pos)
+		}
+	}
+	return s
+}
+
+// growCorridor wanders a snake down the page: long runs east or west, at
+// random, joined by short runs south. The rows of the snake are at least
+// two cells apart so it never touches itself.
+func growCorridor(rng *rand.Rand, vertices int) *cells {
+	s := newCells()
+	path := []cell{{0, 0}}
+	s.add(path[0])
+	dir := 2
+	for s.corners < vertices {
+		if dir == 2 {
+			dir = 1 + 2*rng.Intn(2)
+			path = s.extend(path, dir, 2+rng.Intn(12))
+		} else {
+			dir = 2
+			path = s.extend(path, dir, 2+rng.Intn(3))
+		}
+	}
+	return s
+}
+
+// extend pushes the snake up to length cells in direction dir, stopping
+// early where it would touch itself, and returns the longer path
+func (s *cells) extend(path []cell, dir, length int) []cell {
+	d := sides[dir]
+	for j := 0; j < length; j++ {
+		prev := path[len(path)-1]
+		c := cell{prev.X + d.X, prev.Y + d.Y}
+		for _, r := range ring {
+			n := cell{c.X + r.X, c.Y + r.Y}
+			if s.set[n] && n != prev && geometry.Abs(n.X-prev.X)+geometry.Abs(n.Y-prev.Y) != 1 {
+				return path
+			}
+		}
+		s.add(c)
+		path = append(path, c)
+	}
+	return path
+}
+
+// trace walks the outline clockwise on screen and returns its vertices,
+// starting from the top left corner
+func (s *cells) trace() []cell {
+	next := map[cell]cell{}
+	for c := range s.set {
+		if !s.set[cell{c.X, c.Y - 1}] {
+			next[cell{c.X, c.Y}] = cell{c.X + 1, c.Y}
+		}
+		if !s.set[cell{c.X + 1, c.Y}] {
+			next[cell{c.X + 1, c.Y}] = cell{c.X + 1, c.Y + 1}
+		}
+		if !s.set[cell{c.X, c.Y + 1}] {
+			next[cell{c.X + 1, c.Y + 1}] = cell{c.X, c.Y + 1}
+		}
+		if !s.set[cell{c.X - 1, c.Y}] {
+			next[cell{c.X, c.Y + 1}] = cell{c.X, c.Y}
+		}
+	}
+
+	var start cell
+	first := true
+	for p := range next {
+		if first || p.Y < start.Y || p.Y == start.Y && p.X < start.X {
+			start, first = p, false
+		}
+	}
+
+	var outline []cell
+	p := start
+	for {
+		if s.isCorner(p.X, p.Y) {
+			outline = append(outline, p)
+		}
+		p = next[p]
+		if p == start {
+			return outline
+		}
+	}
+}
+
+// stretch maps the grid lines used by the outline onto distinct random
+// even coordinates in [0, maxCoord], keeping their order, then packs some
+// of them a tile apart when oneTile is set
+func stretch(rng *rand.Rand, outline []cell, maxCoord int, oneTile bool) ([]geometry.Point, error) {
+	xs := lines(outline, func(c cell) int { return c.X })
+	ys := lines(outline, func(c cell) int { return c.Y })
+	slots := maxCoord/2 + 1
+	if len(xs) > slots || len(ys) > slots {
+		return nil, fmt.Errorf("%w: need %d even values, [0, %d] has %d", ErrTooSmall, max(len(xs), len(ys)), maxCoord, slots)
+	}
+	xAt := spread(rng, xs, slots)
+	yAt := spread(rng, ys, slots)
+	if oneTile {
+		pack(rng, xs, xAt)
+		pack(rng, ys, yAt)
+	}
+
+	points := make([]geometry.Point, len(outline))
+	for i, c := range outline {
+		points[i] = geometry.Point{X: xAt[c.X], Y: yAt[c.Y]}
+	}
+	return points, nil
+}
+
+func lines(outline []cell, coord func(cell) int) []int {
+	var vals []int
+	for _, c := range outline {
+		vals = append(vals, coord(c))
+	}
+	slices.Sort(vals)
+	return slices.Compact(vals)
+}
+
+// spread assigns each grid line a random even coordinate below 2*slots
+func spread(rng *rand.Rand, vals []int, slots int) map[int]int {
+	chosen := map[int]bool{}
+	for len(chosen) < len(vals) {
+		chosen[rng.Intn(slots)] = true
+	}
+	picks := make([]int, 0, len(chosen))
+	for v := range chosen {
+		picks = append(picks, v)
+	}
+	slices.Sort(picks)
+
+	at := make(map[int]int, len(vals))
+	for i, v := range vals {
+		at[v] = 2 * picks[i]
+	}
+	return at
+}
+
+// pack moves each grid line after the first, with even odds, to a single
+// tile after the one before it. The lines start at least two apart, so
+// they stay distinct and in order.
+func pack(rng *rand.Rand, vals []int, at map[int]int) {
+	for i := 1; i < len(vals); i++ {
+		if rng.Intn(2) == 0 {
+			at[vals[i]] = at[vals[i-1]] + 1
+		}
+	}
+}
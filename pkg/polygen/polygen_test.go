@@ -0,0 +1,149 @@
+package polygen
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"aoc/pkg/geometry"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, shape := range []Shape{Notched, Spiral, Corridor} {
+		for _, vertices := range []int{4, 10, 50, 500} {
+			for seed := int64(0); seed < 10; seed++ {
+				opts := Options{Seed: seed, Shape: shape, Vertices: vertices, MaxCoord: 100000}
+				points, err := Generate(opts)
+				if err != nil {
+					t.Fatalf("%+v: %v", opts, err)
+				}
+				if len(points) < vertices || len(points) > vertices+6 {
+					t.Errorf("%+v: got %d vertices", opts, len(points))
+				}
+				for _, p := range points {
+					if p.X < 0 || p.Y < 0 || p.X > opts.MaxCoord || p.Y > opts.MaxCoord || p.X%2 != 0 || p.Y%2 != 0 {
+						t.Fatalf("%+v: vertex %v outside the even range", opts, p)
+					}
+				}
+				if err := geometry.ValidatePolygon(points, nil); err != nil {
+					t.Fatalf("%+v: %v", opts, err)
+				}
+				if !geometry.Clockwise(points) {
+					t.Fatalf("%+v: vertices run counter-clockwise", opts)
+				}
+			}
+		}
+	}
+}
+
+// TestGenerateOneTileGaps checks that packed polygons stay valid and in
+// range, and that they do have parallel edges a tile apart
+func TestGenerateOneTileGaps(t *testing.T) {
+	for _, shape := range []Shape{Notched, Spiral, Corridor} {
+		for seed := int64(0); seed < 10; seed++ {
+			opts := Options{Seed: seed, Shape: shape, Vertices: 30, MaxCoord: 1000, OneTileGaps: true}
+			points, err := Generate(opts)
+			if err != nil {
+				t.Fatalf("%+v: %v", opts, err)
+			}
+			for _, p := range points {
+				if p.X < 0 || p.Y < 0 || p.X > opts.MaxCoord || p.Y > opts.MaxCoord {
+					t.Fatalf("%+v: vertex %v outside the range", opts, p)
+				}
+			}
+			if err := geometry.ValidatePolygon(points, nil); err != nil {
+				t.Fatalf("%+v: %v", opts, err)
+			}
+			if !hasOneTileGap(points) {
+				t.Errorf("%+v: no parallel edges a tile apart", opts)
+			}
+		}
+	}
+}
+
+// hasOneTileGap reports whether two overlapping parallel edges are one
+// tile apart
+func hasOneTileGap(points []geometry.Point) bool {
+	n := len(points)
+	for i := range points {
+		a, b := points[i], points[(i+1)%n]
+		for j := range points {
+			c, d := points[j], points[(j+1)%n]
+			switch {
+			case a.X == b.X && c.X == d.X && c.X == a.X+1:
+				if max(min(a.Y, b.Y), min(c.Y, d.Y)) < min(max(a.Y, b.Y), max(c.Y, d.Y)) {
+					return true
+				}
+			case a.Y == b.Y && c.Y == d.Y && c.Y == a.Y+1:
+				if max(min(a.X, b.X), min(c.X, d.X)) < min(max(a.X, b.X), max(c.X, d.X)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	opts := Options{Seed: 42, Shape: Corridor, Vertices: 100, MaxCoord: 1000}
+	a, err := Generate(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Generate(opts)
+	if !slices.Equal(a, b) {
+		t.Errorf("same options gave different polygons")
+	}
+	opts.Seed++
+	if c, _ := Generate(opts); slices.Equal(a, c) {
+		t.Errorf("different seeds gave the same polygon")
+	}
+}
+
+func TestGenerateTooSmall(t *testing.T) {
+	_, err := Generate(Options{Shape: Spiral, Vertices: 100, MaxCoord: 20})
+	if !errors.Is(err, ErrTooSmall) {
+		t.Errorf("expected ErrTooSmall, got %v", err)
+	}
+}
+
+func TestWriteFileRoundTrip(t *testing.T) {
+	points, err := Generate(Options{Seed: 3, Vertices: 30, MaxCoord: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "input.txt")
+	if err := WriteFile(filename, points); err != nil {
+		t.Fatal(err)
+	}
+	read, err := geometry.ReadPoints(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(points, read) {
+		t.Errorf("read back %v, wrote %v", read, points)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, []geometry.Point{{X: 7, Y: 1}, {X: 11, Y: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "7,1\n11,1\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestParseShape(t *testing.T) {
+	for _, shape := range []Shape{Notched, Spiral, Corridor} {
+		if got, err := ParseShape(shape.String()); err != nil || got != shape {
+			t.Errorf("ParseShape(%q) = %v, %v", shape.String(), got, err)
+		}
+	}
+	if _, err := ParseShape("star"); err == nil {
+		t.Errorf("expected an error for an unknown shape")
+	}
+}
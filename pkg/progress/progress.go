@@ -0,0 +1,64 @@
+// Package progress lets long-running solvers report how far along they are
+// without printing anything themselves.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Report is a snapshot of a solve: Done of Total units of work finished and
+// the best answer found so far
+type Report struct {
+	Done, Total int
+	Best        int64
+}
+
+func (r Report) String() string {
+	pct := 100.0
+	if r.Total > 0 {
+		pct = float64(r.Done) * 100 / float64(r.Total)
+	}
+	return fmt.Sprintf("%d/%d (%.1f%%) best %d", r.Done, r.Total, pct, r.Best)
+}
+
+// Func receives reports. Solvers never call it from two goroutines at once,
+// and a nil Func is allowed and ignored.
+type Func func(Report)
+
+// Send calls f with r unless f is nil
+func (f Func) Send(r Report) {
+	if f != nil {
+		f(r)
+	}
+}
+
+// StatusLine returns a Func that redraws one terminal line on w with the
+// latest report, at most once per interval and always for the final
+// report. finish ends the line.
+func StatusLine(w io.Writer, label string, interval time.Duration) (report Func, finish func()) {
+	var mu sync.Mutex
+	var last time.Time
+	drawn := false
+	report = func(r Report) {
+		mu.Lock()
+		defer mu.Unlock()
+		now := time.Now()
+		if r.Done < r.Total && now.Sub(last) < interval {
+			return
+		}
+		last = now
+		drawn = true
+		fmt.Fprintf(w, "\r\033[K%s %v", label, r)
+	}
+	finish = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if drawn {
+			fmt.Fprintln(w)
+		}
+	}
+	return report, finish
+}
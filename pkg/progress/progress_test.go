@@ -0,0 +1,50 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestReportString(t *testing.T) {
+	tests := []struct {
+		r    Report
+		want string
+	}{
+		{Report{Done: 248, Total: 496, Best: 1539809693}, "248/496 (50.0%) best 1539809693"},
+		{Report{}, "0/0 (100.0%) best 0"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSendNil(t *testing.T) {
+	var f Func
+	f.Send(Report{Done: 1, Total: 2}) // must not panic
+}
+
+func TestStatusLine(t *testing.T) {
+	var buf bytes.Buffer
+	report, finish := StatusLine(&buf, "day 9", time.Hour)
+	report(Report{Done: 1, Total: 3, Best: 5})
+	report(Report{Done: 2, Total: 3, Best: 6}) // throttled
+	report(Report{Done: 3, Total: 3, Best: 7})
+	finish()
+
+	want := "\r\033[Kday 9 1/3 (33.3%) best 5" + "\r\033[Kday 9 3/3 (100.0%) best 7\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStatusLineFinishWithoutReports(t *testing.T) {
+	var buf bytes.Buffer
+	_, finish := StatusLine(&buf, "idle", 0)
+	finish()
+	if buf.Len() != 0 {
+		t.Errorf("got %q, want nothing", buf.String())
+	}
+}
@@ -0,0 +1,122 @@
+package viz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"aoc/pkg/geometry"
+)
+
+// SVGOptions chooses what goes on top of the polygon
+type SVGOptions struct {
+	Width      int             // pixels across the longer side; 0 means 800
+	Winner     *geometry.Rect  // drawn solid, if set
+	Candidates []geometry.Rect // drawn dashed with their areas, green when valid and red when not
+}
+
+// SVG colours, named after the puzzle
+const (
+	svgInterior = "#c8f0c8"
+	svgBoundary = "#2e8b57"
+	svgRed      = "#d62728"
+	svgWinner   = "#1f77b4"
+	svgRejected = "#d62728"
+	svgAccepted = "#2ca02c"
+)
+
+// WritePolygonSVG draws poly as it sits on the tile floor: the inside
+// filled slab by slab, the green boundary and the red corner tiles, then
+// the candidate rectangles and the winner. The drawing keeps puzzle
+// coordinates in its viewBox and is scaled to opts.Width pixels.
+func WritePolygonSVG(w io.Writer, poly *geometry.RectilinearPolygon, opts SVGOptions) error {
+	width := opts.Width
+	if width <= 0 {
+		width = 800
+	}
+	minX, maxX, minY, maxY := geometry.Bounds(poly.Vertices)
+	spanX, spanY := maxX-minX+1, maxY-minY+1
+	span := max(spanX, spanY)
+	pad := max(1, span/40)
+	// Sizes in puzzle units that come out the same on any scale
+	dot := float64(span) / 200
+	font := float64(span) / 60
+
+	pxW := width * (spanX + 2*pad) / (span + 2*pad)
+	pxH := width * (spanY + 2*pad) / (span + 2*pad)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="%d %d %d %d">`+"\n",
+		pxW, pxH, minX-pad, minY-pad, spanX+2*pad, spanY+2*pad)
+	fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="white"/>`+"\n",
+		minX-pad, minY-pad, spanX+2*pad, spanY+2*pad)
+
+	fmt.Fprintln(bw, `<g id="interior" fill="`+svgInterior+`">`)
+	for _, slab := range poly.Slabs {
+		for in := range slab.InsideXRanges.All() {
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d"/>`+"\n",
+				in.Lo, slab.YMin, in.Hi-in.Lo, slab.YMax-slab.YMin)
+		}
+	}
+	fmt.Fprintln(bw, `</g>`)
+
+	fmt.Fprint(bw, `<polygon id="boundary" fill="none" stroke="`+svgBoundary+`" stroke-width="2" vector-effect="non-scaling-stroke" points="`)
+	for i, v := range poly.Vertices {
+		if i > 0 {
+			fmt.Fprint(bw, " ")
+		}
+		fmt.Fprintf(bw, "%d,%d", v.X, v.Y)
+	}
+	fmt.Fprintln(bw, `"/>`)
+
+	fmt.Fprintln(bw, `<g id="red-tiles" fill="`+svgRed+`">`)
+	for _, v := range poly.Vertices {
+		fmt.Fprintf(bw, `<circle cx="%d" cy="%d" r="%.2f"/>`+"\n", v.X, v.Y, dot)
+	}
+	fmt.Fprintln(bw, `</g>`)
+
+	if len(opts.Candidates) > 0 {
+		fmt.Fprintf(bw, `<g id="candidates" fill="none" stroke-width="1" stroke-dasharray="4 3" vector-effect="non-scaling-stroke" font-size="%.2f" font-family="monospace">`+"\n", font)
+		for _, r := range opts.Candidates {
+			colour := svgRejected
+			if poly.ContainsTiles(r.A, r.B) {
+				colour = svgAccepted
+			}
+			x, y, rw, rh := svgRect(r)
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" stroke="%s"/>`+"\n", x, y, rw, rh, colour)
+			fmt.Fprintf(bw, `<text x="%d" y="%.2f" fill="%s" stroke="none">%d</text>`+"\n", x, float64(y)+font, colour, r.Area())
+		}
+		fmt.Fprintln(bw, `</g>`)
+	}
+
+	if opts.Winner != nil {
+		x, y, rw, rh := svgRect(*opts.Winner)
+		fmt.Fprintf(bw, `<rect id="winner" x="%d" y="%d" width="%d" height="%d" fill="%s" fill-opacity="0.25" stroke="%s" stroke-width="3" vector-effect="non-scaling-stroke"/>`+"\n",
+			x, y, rw, rh, svgWinner, svgWinner)
+		fmt.Fprintf(bw, `<text x="%d" y="%.2f" fill="%s" font-size="%.2f" font-family="monospace">%d</text>`+"\n",
+			x, float64(y)-font/3, svgWinner, font*1.5, opts.Winner.Area())
+	}
+
+	fmt.Fprintln(bw, `</svg>`)
+	return bw.Flush()
+}
+
+// WritePolygonSVGFile is WritePolygonSVG into a new file
+func WritePolygonSVGFile(filename string, poly *geometry.RectilinearPolygon, opts SVGOptions) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := WritePolygonSVG(f, poly, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// svgRect is r as an SVG rect between its corner tile centres
+func svgRect(r geometry.Rect) (x, y, w, h int) {
+	x, y = min(r.A.X, r.B.X), min(r.A.Y, r.B.Y)
+	return x, y, max(r.A.X, r.B.X) - x, max(r.A.Y, r.B.Y) - y
+}
@@ -0,0 +1,60 @@
+package viz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"aoc/pkg/geometry"
+)
+
+func TestWritePolygonSVG(t *testing.T) {
+	// An L: a 10x4 bar with a 4x6 leg hanging from its left end
+	poly := geometry.NewRectilinearPolygon([]geometry.Point{
+		{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 10}, {X: 0, Y: 10},
+	})
+	winner := geometry.Rect{A: geometry.Point{X: 0, Y: 0}, B: geometry.Point{X: 10, Y: 4}}
+	var buf bytes.Buffer
+	err := WritePolygonSVG(&buf, poly, SVGOptions{
+		Width:  400,
+		Winner: &winner,
+		Candidates: []geometry.Rect{
+			{A: geometry.Point{X: 0, Y: 0}, B: geometry.Point{X: 10, Y: 10}},
+			{A: geometry.Point{X: 0, Y: 0}, B: geometry.Point{X: 4, Y: 10}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	for _, want := range []string{
+		`width="400" height="400" viewBox="-1 -1 13 13"`,
+		`<rect x="0" y="0" width="10" height="4"/>`, // the bar slab
+		`<rect x="0" y="4" width="4" height="6"/>`,  // the leg slab
+		`<rect x="0" y="0" width="10" height="10" stroke="` + svgRejected + `"/>`,
+		`<rect x="0" y="0" width="4" height="10" stroke="` + svgAccepted + `"/>`,
+		`>121</text>`,
+		`<rect id="winner" x="0" y="0" width="10" height="4"`,
+		`>55</text>`,
+		`</svg>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG lacks %q:\n%s", want, svg)
+		}
+	}
+}
+
+func TestWritePolygonSVGWithoutOverlays(t *testing.T) {
+	poly := geometry.NewRectilinearPolygon([]geometry.Point{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 0, Y: 10}})
+	var buf bytes.Buffer
+	if err := WritePolygonSVG(&buf, poly, SVGOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	if !strings.Contains(svg, `width="800" height="`) {
+		t.Errorf("expected the default width: %s", svg)
+	}
+	if strings.Contains(svg, "winner") || strings.Contains(svg, "candidates") {
+		t.Errorf("drew overlays that were not asked for: %s", svg)
+	}
+}
@@ -0,0 +1,148 @@
+// Package viz renders puzzle state as frames: ANSI animations in the terminal,
+// or plain text in headless mode so tests can compare them against a file.
+package viz
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	boldOn      = "\x1b[1;33m"
+	boldOff     = "\x1b[0m"
+)
+
+// Options controls how often and how fast frames are rendered
+type Options struct {
+	Delay time.Duration // pause after each terminal frame; ignored when headless
+	Every int           // render one frame every Every steps; <= 1 renders all of them
+}
+
+// Style decorates a cell that should stand out in a frame
+type Style func(s string) string
+
+// Plain leaves the cell untouched
+func Plain(s string) string { return s }
+
+// Bold highlights the cell with ANSI escapes
+func Bold(s string) string { return boldOn + s + boldOff }
+
+// Animator samples simulation steps into frames and writes them out
+type Animator struct {
+	w        io.Writer
+	opts     Options
+	headless bool
+	step     int
+	rendered int // last step that produced a frame
+}
+
+// NewTerminal animates frames in place on an ANSI terminal
+func NewTerminal(w io.Writer, opts Options) *Animator {
+	return &Animator{w: w, opts: opts, rendered: -1}
+}
+
+// NewHeadless writes every sampled frame one after another with no escapes
+// and no delay
+func NewHeadless(w io.Writer, opts Options) *Animator {
+	return &Animator{w: w, opts: opts, headless: true, rendered: -1}
+}
+
+// Emphasis is the Style renderers should use for highlighted cells
+func (a *Animator) Emphasis() Style {
+	if a.headless {
+		return Plain
+	}
+	return Bold
+}
+
+// Start renders the initial state as step 0
+func (a *Animator) Start(title, frame string) error {
+	return a.render(title, frame)
+}
+
+// Step advances the step counter and renders the frame if it is sampled
+func (a *Animator) Step(title, frame string) error {
+	a.step++
+	if a.opts.Every > 1 && a.step%a.opts.Every != 0 {
+		return nil
+	}
+	return a.render(title, frame)
+}
+
+// Done renders the final state unless the last Step already did
+func (a *Animator) Done(title, frame string) error {
+	if a.rendered == a.step {
+		return nil
+	}
+	return a.render(title, frame)
+}
+
+func (a *Animator) render(title, frame string) error {
+	a.rendered = a.step
+	if a.headless {
+		_, err := fmt.Fprintf(a.w, "== step %d: %s ==\n%s\n", a.step, title, frame)
+		return err
+	}
+	if _, err := fmt.Fprintf(a.w, "%sstep %d: %s\n%s\n", clearScreen, a.step, title, frame); err != nil {
+		return err
+	}
+	time.Sleep(a.opts.Delay)
+	return nil
+}
+
+// Stacks draws crate stacks as columns in the same layout as the puzzle
+// input. Each stack is listed top first; hot reports which crates to
+// highlight with em.
+func Stacks(stacks [][]string, hot func(stack, depth int) bool, em Style) string {
+	height := 0
+	for _, s := range stacks {
+		height = max(height, len(s))
+	}
+	var sb strings.Builder
+	for row := height; row > 0; row-- {
+		line := make([]string, len(stacks))
+		for i, s := range stacks {
+			depth := len(s) - row
+			if depth < 0 {
+				line[i] = "   "
+				continue
+			}
+			crate := "[" + s[depth] + "]"
+			if hot != nil && hot(i, depth) {
+				crate = em(crate)
+			}
+			line[i] = crate
+		}
+		sb.WriteString(strings.TrimRight(strings.Join(line, " "), " "))
+		sb.WriteByte('\n')
+	}
+	labels := make([]string, len(stacks))
+	for i := range stacks {
+		labels[i] = " " + strconv.Itoa(i+1) + " "
+	}
+	sb.WriteString(strings.TrimRight(strings.Join(labels, " "), " "))
+	return sb.String()
+}
+
+// Digits draws a grid of single digit cells, one row per line; hot reports
+// which cells to highlight with em
+func Digits(grid [][]int, hot func(r, c int) bool, em Style) string {
+	var sb strings.Builder
+	for r, row := range grid {
+		if r > 0 {
+			sb.WriteByte('\n')
+		}
+		for c, v := range row {
+			cell := strconv.Itoa(v)
+			if hot != nil && hot(r, c) {
+				cell = em(cell)
+			}
+			sb.WriteString(cell)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package viz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAnimatorSampling(t *testing.T) {
+	tests := []struct {
+		name      string
+		every     int
+		steps     int
+		wantSteps []string
+	}{
+		{"every step", 1, 3, []string{"0", "1", "2", "3"}},
+		{"every 2nd, final sampled", 2, 4, []string{"0", "2", "4"}},
+		{"every 2nd, final forced", 2, 5, []string{"0", "2", "4", "5"}},
+		{"no steps", 3, 0, []string{"0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			a := NewHeadless(&buf, Options{Every: tt.every})
+			if err := a.Start("start", "x"); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < tt.steps; i++ {
+				if err := a.Step("s", "x"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := a.Done("end", "x"); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, l := range strings.Split(buf.String(), "\n") {
+				if strings.HasPrefix(l, "== step ") {
+					got = append(got, strings.TrimSuffix(strings.Fields(l)[2], ":"))
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantSteps, ",") {
+				t.Errorf("rendered steps = %v, want %v", got, tt.wantSteps)
+			}
+		})
+	}
+}
+
+func TestStacks(t *testing.T) {
+	got := Stacks([][]string{{"N", "Z"}, {"D", "C", "M"}, {"P"}}, nil, Plain)
+	want := "    [D]\n[N] [C]\n[Z] [M] [P]\n 1   2   3"
+	if got != want {
+		t.Errorf("Stacks() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDigitsHighlight(t *testing.T) {
+	got := Digits([][]int{{1, 0}, {0, 2}}, func(r, c int) bool { return r == c }, func(s string) string { return "<" + s + ">" })
+	want := "<1>0\n0<2>"
+	if got != want {
+		t.Errorf("Digits() = %q, want %q", got, want)
+	}
+}
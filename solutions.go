@@ -0,0 +1,24 @@
+package main
+
+import (
+	"runtime"
+
+	"aoc/2025/day09"
+	day09opus "aoc/2025/day09-opus"
+	"aoc/pkg/geometry"
+	"aoc/pkg/harness"
+)
+
+// rectangles holds the 2025 day 09 solutions, which all take the red tiles
+// and answer with the largest valid rectangle
+var rectangles = harness.NewRegistry[[]geometry.Point, int64]()
+
+var day09Part2 = harness.Key{Year: 2025, Day: 9, Part: 2}
+
+func init() {
+	rectangles.Register(day09Part2, "staircase", day09.LargestRectangle)
+	rectangles.Register(day09Part2, "grid", day09.LargestRectangleGrid)
+	rectangles.Register(day09Part2, "slabs", func(redTiles []geometry.Point) int64 {
+		return day09opus.LargestRectangle(redTiles, runtime.NumCPU())
+	})
+}